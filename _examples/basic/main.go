@@ -21,8 +21,11 @@ func main() {
 
 	// Output:
 	// ERROR:
-	// close go.mod: file already closed
 	// internal error: failed to parse string: strconv.Atoi: parsing "ABC": invalid syntax
+	// cleanup errors (1):
+	//   [1] os.(*File).Close at /path/to/_examples/basic/main.go:52 (took 801ns)
+	//       close go.mod: file already closed
+	//       caused by: file already closed
 	//
 	// is os.ErrClosed?:     true
 	// is ErrInternal?:      true
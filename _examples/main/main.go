@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ras0q/goalie"
+)
+
+func main() {
+	// ✅ Use goalie.Main to run cleanups and report their errors even in main().
+	goalie.Main(func(ctx context.Context, g *goalie.Goalie) error {
+		f, err := os.Open("go.mod")
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+		defer g.Guard(f.Close)
+
+		// ❌ This code close the file explicitly by mistake.
+		_ = f.Close()
+
+		// The context is cancelled on SIGINT or SIGTERM.
+		<-ctx.Done()
+
+		return nil
+	})
+
+	// Output (after Ctrl+C):
+	// cleanup errors (1):
+	//   [1] os.(*File).Close at /path/to/_examples/main/main.go:26 (took 801ns)
+	//       close go.mod: file already closed
+	//       caused by: file already closed
+	//
+	// exit status 3
+}
@@ -0,0 +1,64 @@
+package goalie
+
+import (
+	"io"
+)
+
+// Acquire calls acquire and, only if it succeeds, registers release
+// to be guarded with the acquired value when [Goalie.Collect] is called.
+//
+// This avoids the common mistake of deferring the release before checking the error.
+// Like [Goalie.Defer], the release runs in Collect after all deferred Guards,
+// so release the resources it depends on with Acquire or Defer as well.
+//
+// Example:
+//
+//	f, err := goalie.Acquire(g, func() (*os.File, error) {
+//		return os.Open(path)
+//	}, (*os.File).Close)
+//	if err != nil {
+//		return err
+//	}
+func Acquire[T any](g *Goalie, acquire func() (T, error), release func(T) error) (T, error) {
+	v, err := acquire()
+	if err != nil {
+		return v, err
+	}
+
+	g.deferCleanup(cleanup{
+		label: funcLabel(release),
+		site:  callerSite(),
+		fn: func(error) error {
+			return release(v)
+		},
+	})
+
+	return v, nil
+}
+
+// AcquireCloser is a shortcut of [Acquire] that releases the acquired [io.Closer] with Close.
+//
+// Example:
+//
+//	f, err := goalie.AcquireCloser(g, func() (*os.File, error) {
+//		return os.Open(path)
+//	})
+//	if err != nil {
+//		return err
+//	}
+func AcquireCloser[T io.Closer](g *Goalie, acquire func() (T, error)) (T, error) {
+	v, err := acquire()
+	if err != nil {
+		return v, err
+	}
+
+	g.deferCleanup(cleanup{
+		label: methodLabel(v, "Close"),
+		site:  callerSite(),
+		fn: func(error) error {
+			return v.Close()
+		},
+	})
+
+	return v, nil
+}
@@ -0,0 +1,158 @@
+package goalie_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+type closer struct {
+	closed bool
+	err    error
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return c.err
+}
+
+func Test_Acquire(t *testing.T) {
+	errAcquire := errors.New("acquire")
+	errRelease := errors.New("release")
+
+	type testcase struct {
+		acquireErr       error
+		releaseErr       error
+		isReleased       bool
+		isAcquireError   bool
+		isReleaseError   bool
+		useAcquireCloser bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		c := &closer{err: tc.releaseErr}
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+
+			acquire := func() (*closer, error) {
+				if tc.acquireErr != nil {
+					return nil, tc.acquireErr
+				}
+				return c, nil
+			}
+
+			if tc.useAcquireCloser {
+				_, err = goalie.AcquireCloser(g, acquire)
+			} else {
+				_, err = goalie.Acquire(g, acquire, (*closer).Close)
+			}
+			if err != nil {
+				return err
+			}
+
+			// The resource must not be released before Collect.
+			assert(t, false, c.closed)
+
+			return nil
+		}()
+		assert(t, tc.isReleased, c.closed)
+		assert(t, tc.isAcquireError, errors.Is(err, errAcquire))
+		assert(t, tc.isReleaseError, errors.Is(err, errRelease))
+	}
+
+	testcases := map[string]testcase{
+		"release after successful acquisition": {
+			isReleased: true,
+		},
+		"collect release error": {
+			releaseErr:     errRelease,
+			isReleased:     true,
+			isReleaseError: true,
+		},
+		"do not release after failed acquisition": {
+			acquireErr:     errAcquire,
+			isReleased:     false,
+			isAcquireError: true,
+		},
+		"closer: collect release error": {
+			releaseErr:       errRelease,
+			isReleased:       true,
+			isReleaseError:   true,
+			useAcquireCloser: true,
+		},
+		"closer: do not release after failed acquisition": {
+			acquireErr:       errAcquire,
+			isReleased:       false,
+			isAcquireError:   true,
+			useAcquireCloser: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_AcquireCloser_File(t *testing.T) {
+	var f *os.File
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		f, err = goalie.AcquireCloser(g, func() (*os.File, error) {
+			return os.Open("acquire_test.go")
+		})
+		if err != nil {
+			return err
+		}
+
+		// This closes the file explicitly by mistake.
+		return f.Close()
+	}()
+	assert(t, true, errors.Is(err, os.ErrClosed))
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, "os.(*File).Close", gerr.Cleanups[0].Label)
+}
+
+func Test_Acquire_RunsAfterGuard(t *testing.T) {
+	var order []string
+	release := func(name string) func() error {
+		return func() error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		defer g.Guard(release("file"))
+		_, err = goalie.Acquire(g, func() (string, error) {
+			return "writer", nil
+		}, func(string) error {
+			return release("writer")()
+		})
+		if err != nil {
+			return err
+		}
+		defer g.Guard(release("reader"))
+		g.Defer(release("buffer"))
+
+		return nil
+	}()
+	assert(t, nil, err)
+
+	// Cleanups registered with Acquire and Defer run in Collect, after all deferred Guards.
+	assert(t, "reader,file,buffer,writer", strings.Join(order, ","))
+}
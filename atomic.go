@@ -0,0 +1,139 @@
+package goalie
+
+import (
+	"errors"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// AtomicFile is a temporary file that replaces its destination when [Goalie.Collect] is called,
+// only if the enclosing function succeeds.
+//
+// Do not close it by yourself; the Goalie closes it.
+type AtomicFile struct {
+	*os.File
+	path string
+}
+
+// CreateAtomic creates an [AtomicFile] to be written to path with perm (before umask),
+// and registers its finalization with g.
+//
+// When [Goalie.Collect] is called with no primary error and no cleanup error captured so far,
+// the file is synced, closed and renamed to path.
+// Otherwise, or if any of these steps fails, the file is closed and removed, leaving path untouched.
+// Each failing step is captured as a separate error.
+//
+// Example:
+//
+//	f, err := goalie.CreateAtomic(g, "config.json", 0o644)
+//	if err != nil {
+//		return err
+//	}
+//	return json.NewEncoder(f).Encode(config)
+func CreateAtomic(g *Goalie, path string, perm fs.FileMode) (*AtomicFile, error) {
+	f, err := createTemp(path, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	site := callerSite()
+	tmp := f.Name()
+	step := func(label string, fn func(primary error) error) cleanup {
+		return cleanup{
+			label: label,
+			site:  site,
+			fn:    fn,
+		}
+	}
+	// committing reports whether the file should replace path.
+	// Any error captured so far, including those of the cleanups
+	// that wrap f and run before it, aborts the commit.
+	committing := func(primary error) bool {
+		return primary == nil && !g.failed()
+	}
+
+	// Registered cleanups run in reverse order.
+	steps := []cleanup{
+		step("remove "+tmp, func(primary error) error {
+			if committing(primary) {
+				return nil
+			}
+			if err := os.Remove(tmp); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			return nil
+		}),
+		step("rename "+tmp+" to "+path, func(primary error) error {
+			if !committing(primary) {
+				return nil
+			}
+
+			return os.Rename(tmp, path)
+		}),
+		step("close "+tmp, func(error) error {
+			return f.Close()
+		}),
+		step("sync "+tmp, func(primary error) error {
+			if !committing(primary) {
+				return nil
+			}
+
+			return f.Sync()
+		}),
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.cleanups = append(g.cleanups, steps...)
+
+	return &AtomicFile{File: f, path: path}, nil
+}
+
+// createTemp creates a new temporary file next to path with perm (before umask), like [os.WriteFile].
+// Unlike [os.CreateTemp] followed by Chmod, this respects the umask.
+func createTemp(path string, perm fs.FileMode) (*os.File, error) {
+	prefix := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	for range 10000 {
+		name := prefix + strconv.FormatUint(uint64(rand.Uint32()), 10)
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+
+		return f, err
+	}
+
+	return nil, &fs.PathError{Op: "createtemp", Path: prefix + "*", Err: fs.ErrExist}
+}
+
+// failed reports whether any cleanup error has been captured.
+func (g *Goalie) failed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.errs) > 0
+}
+
+// Path returns the destination path of f.
+func (f *AtomicFile) Path() string {
+	return f.path
+}
+
+// WriteFileAtomic writes data to path atomically,
+// like [os.WriteFile] but leaving path untouched on failure.
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	return Run(func(g *Goalie) error {
+		f, err := CreateAtomic(g, path, perm)
+		if err != nil {
+			return err
+		}
+
+		_, err = f.Write(data)
+		return err
+	})
+}
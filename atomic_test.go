@@ -0,0 +1,142 @@
+package goalie_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_CreateAtomic(t *testing.T) {
+	type testcase struct {
+		primaryErr        error
+		layerErr          error
+		guardErr          error
+		closeEarly        bool
+		expectedContent   string
+		expectedLabels    []string
+		isInternalError   bool
+		isFileClosedError bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.txt")
+		err := os.WriteFile(path, []byte("old"), 0o600)
+		assert(t, nil, err)
+
+		err = func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+
+			f, err := goalie.CreateAtomic(g, path, 0o644)
+			if err != nil {
+				return err
+			}
+			assert(t, path, f.Path())
+			if tc.layerErr != nil {
+				// A layer wrapping f, such as a buffered writer, is finalized before f.
+				g.DeferLabel("flush", func() error {
+					return tc.layerErr
+				})
+			}
+
+			if _, err := f.WriteString("new"); err != nil {
+				return err
+			}
+			if tc.guardErr != nil {
+				g.GuardLabel("guard", func() error {
+					return tc.guardErr
+				})
+			}
+			if tc.closeEarly {
+				// This closes the file by mistake.
+				_ = f.Close()
+			}
+
+			return tc.primaryErr
+		}()
+		assert(t, tc.isInternalError, errors.Is(err, errInternal))
+		assert(t, tc.isFileClosedError, errors.Is(err, os.ErrClosed))
+
+		var labels []string
+		var gerr *goalie.Error
+		if errors.As(err, &gerr) {
+			for _, c := range gerr.Cleanups {
+				label, _, _ := strings.Cut(c.Label, " ")
+				labels = append(labels, label)
+			}
+		}
+		assert(t, strings.Join(tc.expectedLabels, ","), strings.Join(labels, ","))
+
+		b, err := os.ReadFile(path)
+		assert(t, nil, err)
+		assert(t, tc.expectedContent, string(b))
+
+		// No temporary file must be left.
+		entries, err := os.ReadDir(dir)
+		assert(t, nil, err)
+		assert(t, 1, len(entries))
+
+		if tc.expectedContent == "new" && runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			assert(t, nil, err)
+			assert(t, os.FileMode(0o644), info.Mode().Perm())
+		}
+	}
+
+	testcases := map[string]testcase{
+		"commit on success": {
+			expectedContent: "new",
+		},
+		"remove on primary error": {
+			primaryErr:      errInternal,
+			expectedContent: "old",
+			isInternalError: true,
+		},
+		"remove on error of a layer registered later": {
+			layerErr:        errInternal,
+			expectedContent: "old",
+			expectedLabels:  []string{"flush"},
+			isInternalError: true,
+		},
+		"remove on error captured before collection": {
+			guardErr:        errInternal,
+			expectedContent: "old",
+			expectedLabels:  []string{"guard"},
+			isInternalError: true,
+		},
+		"remove on finalization error": {
+			closeEarly:        true,
+			expectedContent:   "old",
+			expectedLabels:    []string{"close", "sync"},
+			isFileClosedError: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_WriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+
+	err := goalie.WriteFileAtomic(path, []byte("data"), 0o600)
+	assert(t, nil, err)
+
+	b, err := os.ReadFile(path)
+	assert(t, nil, err)
+	assert(t, "data", string(b))
+
+	err = goalie.WriteFileAtomic(filepath.Join(path, "not a directory"), []byte("data"), 0o600)
+	assert(t, true, err != nil)
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package goalie_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_WriteFileAtomic_Umask(t *testing.T) {
+	oldMask := syscall.Umask(0o022)
+	defer syscall.Umask(oldMask)
+
+	path := filepath.Join(t.TempDir(), "data.txt")
+	err := goalie.WriteFileAtomic(path, []byte("data"), 0o666)
+	assert(t, nil, err)
+
+	info, err := os.Stat(path)
+	assert(t, nil, err)
+	assert(t, os.FileMode(0o644), info.Mode().Perm())
+}
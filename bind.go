@@ -0,0 +1,73 @@
+package goalie
+
+import (
+	"context"
+)
+
+// Binding is a Goalie bound to a context by [Bind].
+type Binding struct {
+	done chan struct{}
+	err  error
+	stop func() bool
+}
+
+// Bind arranges for the cleanups registered with g to be collected when ctx is done,
+// using [context.AfterFunc].
+//
+// This is useful for resources whose lifetime is tied to a context rather than a function,
+// such as those held by a background worker.
+// Cleanups run with no primary error, since the cancellation is the expected end of their lifetime.
+// The collected error is passed to callback if it is not nil, and can also be retrieved with [Binding.Err].
+//
+// Example:
+//
+//	g := goalie.New()
+//	conn, err := goalie.AcquireCloser(g, dial)
+//	if err != nil {
+//		return err
+//	}
+//	goalie.Bind(ctx, g, func(err error) {
+//		if err != nil {
+//			log.Printf("worker cleanup failed: %+v", err)
+//		}
+//	})
+func Bind(ctx context.Context, g *Goalie, callback func(err error)) *Binding {
+	b := &Binding{
+		done: make(chan struct{}),
+	}
+	b.stop = context.AfterFunc(ctx, func() {
+		var err error
+		g.Collect(&err)
+
+		b.err = err
+		close(b.done)
+		if callback != nil {
+			callback(err)
+		}
+	})
+
+	return b
+}
+
+// Done returns a channel that is closed after the cleanups are collected.
+func (b *Binding) Done() <-chan struct{} {
+	return b.done
+}
+
+// Err returns the error collected from the cleanups.
+// It returns nil until the channel returned by [Binding.Done] is closed.
+func (b *Binding) Err() error {
+	select {
+	case <-b.done:
+		return b.err
+	default:
+		return nil
+	}
+}
+
+// Stop detaches g from the context so that its cleanups are not collected on cancellation.
+// It reports whether the binding was stopped before the cleanups started to run;
+// if so, the caller is responsible for collecting g and Done is never closed.
+func (b *Binding) Stop() bool {
+	return b.stop()
+}
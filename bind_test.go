@@ -0,0 +1,99 @@
+package goalie_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Bind(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+
+	type testcase struct {
+		cleanupErr     error
+		stop           bool
+		isStopped      bool
+		isCleanedUp    bool
+		isCleanupError bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cleanedUp := false
+		g := goalie.New()
+		g.Defer(func() error {
+			cleanedUp = true
+			return tc.cleanupErr
+		})
+
+		callbackErr := make(chan error, 1)
+		b := goalie.Bind(ctx, g, func(err error) {
+			callbackErr <- err
+		})
+		assert(t, nil, b.Err())
+
+		if tc.stop {
+			assert(t, tc.isStopped, b.Stop())
+		}
+		cancel()
+
+		if tc.isStopped {
+			select {
+			case <-b.Done():
+				t.Fatal("cleanups are collected after stop")
+			case <-time.After(50 * time.Millisecond):
+			}
+			assert(t, false, cleanedUp)
+			return
+		}
+
+		select {
+		case <-b.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("cleanups are not collected after cancellation")
+		}
+		assert(t, tc.isCleanedUp, cleanedUp)
+		assert(t, tc.isCleanupError, errors.Is(b.Err(), errCleanup))
+		assert(t, tc.isCleanupError, errors.Is(<-callbackErr, errCleanup))
+	}
+
+	testcases := map[string]testcase{
+		"collect on cancellation": {
+			isCleanedUp: true,
+		},
+		"deliver cleanup error": {
+			cleanupErr:     errCleanup,
+			isCleanedUp:    true,
+			isCleanupError: true,
+		},
+		"stop before cancellation": {
+			stop:      true,
+			isStopped: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Bind_NilCallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := goalie.New()
+	g.Defer(func() error { return errInternal })
+
+	b := goalie.Bind(ctx, g, nil)
+	cancel()
+
+	<-b.Done()
+	assert(t, true, errors.Is(b.Err(), errInternal))
+}
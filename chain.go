@@ -0,0 +1,68 @@
+package goalie
+
+import (
+	"io"
+)
+
+// Layer is a layer of a writer chain registered with [Goalie.Chain].
+type Layer struct {
+	// Name labels an error returned by Finalize.
+	Name string
+	// Finalize flushes or closes the layer.
+	Finalize func() error
+}
+
+// FlushLayer returns a [Layer] that flushes f, e.g. a [bufio.Writer].
+func FlushLayer(name string, f interface{ Flush() error }) Layer {
+	return Layer{
+		Name:     name,
+		Finalize: f.Flush,
+	}
+}
+
+// CloseLayer returns a [Layer] that closes c, e.g. a [gzip.Writer] or an [os.File].
+func CloseLayer(name string, c io.Closer) Layer {
+	return Layer{
+		Name:     name,
+		Finalize: c.Close,
+	}
+}
+
+// Chain registers layers of writers to be finalized in order when [Goalie.Collect] is called.
+//
+// Layers must be given in the order data flows through them,
+// from the outermost writer to the underlying one, which is also the order they must be finalized.
+// Every layer is finalized even if a previous one fails,
+// and each failure is captured with the name of its layer.
+//
+// Example:
+//
+//	f, err := os.Create("data.gz")
+//	if err != nil {
+//		return err
+//	}
+//	gz := gzip.NewWriter(f)
+//	bw := bufio.NewWriter(gz)
+//	g.Chain(
+//		goalie.FlushLayer("bufio", bw),
+//		goalie.CloseLayer("gzip", gz),
+//		goalie.CloseLayer("file", f),
+//	)
+func (g *Goalie) Chain(layers ...Layer) {
+	site := callerSite()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Registered cleanups run in reverse order, so register the last layer first.
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+		g.cleanups = append(g.cleanups, cleanup{
+			label: l.Name,
+			site:  site,
+			fn: func(error) error {
+				return l.Finalize()
+			},
+		})
+	}
+}
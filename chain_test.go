@@ -0,0 +1,107 @@
+package goalie_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Chain(t *testing.T) {
+	const data = "hello, goalie"
+
+	writeGzip := func(path string, closeFileEarly bool) (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		gz := gzip.NewWriter(f)
+		bw := bufio.NewWriter(gz)
+		g.Chain(
+			goalie.FlushLayer("bufio", bw),
+			goalie.CloseLayer("gzip", gz),
+			goalie.CloseLayer("file", f),
+		)
+
+		if _, err := bw.WriteString(data); err != nil {
+			return err
+		}
+
+		if closeFileEarly {
+			// This closes the underlying file by mistake, so all layers fail.
+			_ = f.Close()
+		}
+
+		return nil
+	}
+
+	t.Run("finalize in order", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.gz")
+		err := writeGzip(path, false)
+		assert(t, nil, err)
+
+		f, err := os.Open(path)
+		assert(t, nil, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		assert(t, nil, err)
+		b, err := io.ReadAll(gz)
+		assert(t, nil, err)
+		assert(t, data, string(b))
+	})
+
+	t.Run("continue after failures", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.gz")
+		err := writeGzip(path, true)
+		assert(t, true, errors.Is(err, os.ErrClosed))
+
+		var gerr *goalie.Error
+		assert(t, true, errors.As(err, &gerr))
+		labels := make([]string, 0, len(gerr.Cleanups))
+		for _, c := range gerr.Cleanups {
+			labels = append(labels, c.Label)
+		}
+		assert(t, "file,gzip,bufio", strings.Join(labels, ","))
+	})
+}
+
+func Test_Chain_Order(t *testing.T) {
+	var order []string
+	layer := func(name string) goalie.Layer {
+		return goalie.Layer{
+			Name: name,
+			Finalize: func() error {
+				order = append(order, name)
+				return nil
+			},
+		}
+	}
+
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		g.Defer(func() error {
+			order = append(order, "before")
+			return nil
+		})
+		g.Chain(layer("outer"), layer("middle"), layer("inner"))
+		g.Defer(func() error {
+			order = append(order, "after")
+			return nil
+		})
+
+		return nil
+	}()
+	assert(t, nil, err)
+	assert(t, "after,outer,middle,inner,before", strings.Join(order, ","))
+}
@@ -0,0 +1,111 @@
+package goalie
+
+import (
+	"io"
+	"sync"
+)
+
+// Closers is an [io.Closer] that closes a set of resources.
+// It is useful for implementing Close on a type that owns many resources.
+//
+// The zero value is ready to use.
+//
+// Example:
+//
+//	type Server struct {
+//		goalie.Closers
+//		db *sql.DB
+//		l  net.Listener
+//	}
+//
+//	func NewServer() (_ *Server, err error) {
+//		s := &Server{}
+//		defer func() {
+//			if err != nil {
+//				s.Close()
+//			}
+//		}()
+//
+//		if s.db, err = sql.Open("sqlite", "app.db"); err != nil {
+//			return nil, err
+//		}
+//		s.Add(s.db)
+//
+//		if s.l, err = net.Listen("tcp", ":8080"); err != nil {
+//			return nil, err
+//		}
+//		s.Add(s.l)
+//
+//		return s, nil
+//	}
+type Closers struct {
+	mu      sync.Mutex
+	g       *Goalie
+	options []Option
+	closed  bool
+	err     error
+}
+
+// NewClosers returns a new [Closers] that collects errors with the given options.
+func NewClosers(options ...Option) *Closers {
+	return &Closers{
+		options: options,
+	}
+}
+
+// Add appends closers to be closed by [Closers.Close].
+// It must not be called after [Closers.Close].
+func (c *Closers) Add(closers ...io.Closer) {
+	site := callerSite()
+	for _, closer := range closers {
+		c.add(cleanup{
+			label: methodLabel(closer, "Close"),
+			site:  site,
+			fn: func(error) error {
+				return closer.Close()
+			},
+		})
+	}
+}
+
+// AddFunc appends closeFunc to be called by [Closers.Close], labelled with label.
+// It must not be called after [Closers.Close].
+func (c *Closers) AddFunc(label string, closeFunc func() error) {
+	c.add(cleanup{
+		label: label,
+		site:  callerSite(),
+		fn: func(error) error {
+			return closeFunc()
+		},
+	})
+}
+
+func (c *Closers) add(cl cleanup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.g == nil {
+		c.g = New(c.options...)
+	}
+	c.g.deferCleanup(cl)
+}
+
+// Close closes all the added resources in reverse order, even if some of them fail,
+// and returns their errors joined in the same way as [Goalie.Collect].
+//
+// Close is idempotent: subsequent calls return the result of the first call.
+func (c *Closers) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return c.err
+	}
+	c.closed = true
+
+	if c.g != nil {
+		c.g.Collect(&c.err)
+	}
+
+	return c.err
+}
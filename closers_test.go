@@ -0,0 +1,127 @@
+package goalie_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Closers(t *testing.T) {
+	errClose := errors.New("close")
+
+	type testcase struct {
+		errs       []error
+		isNil      bool
+		errorCount int
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var order []int
+		var c goalie.Closers
+		for i, err := range tc.errs {
+			c.AddFunc("close", func() error {
+				order = append(order, i)
+				return err
+			})
+		}
+
+		err := c.Close()
+		assert(t, tc.isNil, err == nil)
+		assert(t, len(tc.errs), len(order))
+		for i, n := range order {
+			assert(t, len(tc.errs)-1-i, n)
+		}
+
+		if err != nil {
+			var gerr *goalie.Error
+			assert(t, true, errors.As(err, &gerr))
+			assert(t, tc.errorCount, len(gerr.Cleanups))
+			assert(t, true, errors.Is(err, errClose))
+		}
+
+		// Close is idempotent.
+		assert(t, err, c.Close())
+		assert(t, len(tc.errs), len(order))
+	}
+
+	testcases := map[string]testcase{
+		"no closers": {
+			isNil: true,
+		},
+		"close all in reverse order": {
+			errs:  []error{nil, nil, nil},
+			isNil: true,
+		},
+		"collect every error": {
+			errs:       []error{errClose, nil, errClose},
+			errorCount: 2,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Closers_Add(t *testing.T) {
+	errWrapped := errors.New("wrapped")
+	ok := &closer{}
+	failing := &closer{err: errInternal}
+
+	c := goalie.NewClosers(goalie.WithWrapErrorFunc(func(err error) error {
+		return errors.Join(errWrapped, err)
+	}))
+	c.Add(ok, failing)
+
+	err := c.Close()
+	assert(t, true, ok.closed)
+	assert(t, true, failing.closed)
+	assert(t, true, errors.Is(err, errInternal))
+	assert(t, true, errors.Is(err, errWrapped))
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, "github.com/ras0q/goalie_test.(*closer).Close", gerr.Cleanups[0].Label)
+}
+
+func Test_Closers_LabelMatchesGuard(t *testing.T) {
+	labelOf := func(register func(g *goalie.Goalie, c io.Closer)) string {
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+
+			register(g, &closer{err: errInternal})
+
+			return nil
+		}()
+
+		var gerr *goalie.Error
+		assert(t, true, errors.As(err, &gerr))
+		return gerr.Cleanups[0].Label
+	}
+
+	expected := labelOf(func(g *goalie.Goalie, c io.Closer) {
+		g.Guard(c.(*closer).Close)
+	})
+	assert(t, "github.com/ras0q/goalie_test.(*closer).Close", expected)
+	assert(t, expected, labelOf(func(g *goalie.Goalie, c io.Closer) {
+		g.Defer(c.(*closer).Close)
+	}))
+	assert(t, expected, labelOf(func(g *goalie.Goalie, c io.Closer) {
+		_, _ = goalie.AcquireCloser(g, func() (io.Closer, error) {
+			return c, nil
+		})
+	}))
+
+	var c goalie.Closers
+	c.Add(io.Closer(&closer{err: errInternal}))
+	var gerr *goalie.Error
+	assert(t, true, errors.As(c.Close(), &gerr))
+	assert(t, expected, gerr.Cleanups[0].Label)
+}
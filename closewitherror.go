@@ -0,0 +1,41 @@
+package goalie
+
+import (
+	"io"
+)
+
+// ErrorCloser is implemented by closers that can tell their peer why they are closed,
+// such as [io.PipeWriter] and [io.PipeReader].
+type ErrorCloser interface {
+	CloseWithError(err error) error
+}
+
+// DeferCloseWithError registers c to be closed when [Goalie.Collect] is called.
+//
+// If the enclosing function fails, c is closed with CloseWithError and its primary error,
+// so that the peer of c learns why it stopped.
+// Otherwise, c is closed with Close if it implements [io.Closer], or with CloseWithError(nil).
+//
+// Example:
+//
+//	pr, pw := io.Pipe()
+//	go func() (err error) {
+//		g := goalie.New()
+//		defer g.Collect(&err)
+//		g.DeferCloseWithError(pw)
+//
+//		return json.NewEncoder(pw).Encode(v)
+//	}()
+func (g *Goalie) DeferCloseWithError(c ErrorCloser) {
+	g.deferCleanup(cleanup{
+		label: methodLabel(c, "CloseWithError"),
+		site:  callerSite(),
+		fn: func(primary error) error {
+			if closer, ok := c.(io.Closer); ok && primary == nil {
+				return closer.Close()
+			}
+
+			return c.CloseWithError(primary)
+		},
+	})
+}
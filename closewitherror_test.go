@@ -0,0 +1,95 @@
+package goalie_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+// errorCloser is an ErrorCloser without Close.
+type errorCloser struct {
+	closedWith error
+	closed     bool
+}
+
+func (c *errorCloser) CloseWithError(err error) error {
+	c.closed = true
+	c.closedWith = err
+	return nil
+}
+
+func Test_DeferCloseWithError(t *testing.T) {
+	type testcase struct {
+		primaryErr  error
+		expectedErr error
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		pr, pw := io.Pipe()
+		errc := make(chan error, 1)
+		go func() {
+			errc <- func() (err error) {
+				g := goalie.New()
+				defer g.Collect(&err)
+				g.DeferCloseWithError(pw)
+
+				if _, err := pw.Write([]byte("data")); err != nil {
+					return err
+				}
+
+				return tc.primaryErr
+			}()
+		}()
+
+		b, err := io.ReadAll(pr)
+		assert(t, "data", string(b))
+		assert(t, tc.expectedErr, err)
+		assert(t, tc.primaryErr, <-errc)
+	}
+
+	testcases := map[string]testcase{
+		"close without error": {
+			primaryErr:  nil,
+			expectedErr: nil,
+		},
+		"close with primary error": {
+			primaryErr:  errInternal,
+			expectedErr: errInternal,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_DeferCloseWithError_WithoutClose(t *testing.T) {
+	c := &errorCloser{}
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+		g.DeferCloseWithError(c)
+
+		return nil
+	}()
+	assert(t, nil, err)
+	assert(t, true, c.closed)
+	assert(t, nil, c.closedWith)
+
+	c = &errorCloser{}
+	err = func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+		g.DeferCloseWithError(c)
+
+		return errInternal
+	}()
+	assert(t, true, errors.Is(err, errInternal))
+	assert(t, errInternal, c.closedWith)
+}
@@ -0,0 +1,239 @@
+package goalie
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultMaxConcurrency is the default value of [WithMaxConcurrency].
+// Cleanups are usually I/O bound, so it does not depend on the number of CPUs.
+const defaultMaxConcurrency = 16
+
+// DeferConcurrent is like [Goalie.Defer] but marks errFunc as independent of other cleanups.
+//
+// When [Goalie.Collect] is called, adjacent independent cleanups run concurrently,
+// while a cleanup registered with [Goalie.Defer] waits for them and keeps the reverse order of registration.
+// Like other registered cleanups, their errors are reported in order of registration,
+// regardless of which finishes first; see [Goalie.Collect] for errors captured by Guard.
+//
+// Example:
+//
+//	for _, conn := range conns {
+//		g.DeferConcurrent(conn.Close)
+//	}
+func (g *Goalie) DeferConcurrent(errFunc func() error) {
+	g.deferCleanup(cleanup{
+		label: funcLabel(errFunc),
+		site:  callerSite(),
+		fn: func(error) error {
+			return errFunc()
+		},
+		concurrent: true,
+	})
+}
+
+// DeferConcurrentLabel is like [Goalie.DeferConcurrent] but labels the captured error with label
+// instead of the function name.
+func (g *Goalie) DeferConcurrentLabel(label string, errFunc func() error) {
+	g.deferCleanup(cleanup{
+		label: label,
+		site:  callerSite(),
+		fn: func(error) error {
+			return errFunc()
+		},
+		concurrent: true,
+	})
+}
+
+// WithMaxConcurrency sets the maximum number of cleanups registered with
+// [Goalie.DeferConcurrent] that run at the same time.
+// By default, up to 16 cleanups run at the same time.
+func WithMaxConcurrency(n int) Option {
+	return func(g *Goalie) {
+		g.maxConcurrency = n
+	}
+}
+
+// collection tracks the errors recorded while [Goalie.Collect] runs the registered cleanups,
+// so that they can be sorted in order of registration.
+type collection struct {
+	// base is the number of errors recorded before the cleanups started.
+	base int
+	// indices holds the registration index of the cleanup each error after base belongs to.
+	indices []int
+	// current is the registration index of the running sequential cleanup,
+	// or of the first cleanup of the running concurrent batch.
+	current int
+	// owners maps the goroutines running concurrent cleanups to their registration indices.
+	owners map[uint64]int
+}
+
+// runCleanups runs all registered cleanups in reverse order of registration,
+// running adjacent concurrent cleanups at the same time.
+// Their errors, including those captured by Guard calls inside them, are recorded in order of registration,
+// regardless of the order in which they run or finish.
+func (g *Goalie) runCleanups(primary error) {
+	g.mu.Lock()
+	cleanups := g.cleanups
+	g.cleanups = nil
+	c := &collection{
+		base:   len(g.errs),
+		owners: make(map[uint64]int),
+	}
+	g.collecting = c
+	g.mu.Unlock()
+
+	defer g.finishCollecting()
+
+	for i := len(cleanups) - 1; i >= 0; {
+		cl := cleanups[i]
+		if !cl.concurrent {
+			g.mu.Lock()
+			c.current = i
+			g.mu.Unlock()
+
+			if err := g.capture(origin{label: cl.label, site: cl.site}, func() error {
+				return cl.fn(primary)
+			}); err != nil {
+				g.mu.Lock()
+				g.appendErr(err, i)
+				g.mu.Unlock()
+			}
+			i--
+			continue
+		}
+
+		j := i
+		for j >= 0 && cleanups[j].concurrent {
+			j--
+		}
+		g.mu.Lock()
+		c.current = j + 1
+		g.mu.Unlock()
+
+		g.runConcurrently(cleanups[j+1:i+1], j+1, primary)
+		i = j
+	}
+}
+
+// appendErr records err, attributing it to the cleanup with the registration index
+// while the registered cleanups are running.
+// If index is negative, err is attributed to the cleanup running in the current goroutine.
+// g.mu must be held.
+func (g *Goalie) appendErr(err error, index int) {
+	g.errs = append(g.errs, err)
+
+	c := g.collecting
+	if c == nil {
+		return
+	}
+	if index < 0 {
+		index = c.current
+		if len(c.owners) > 0 {
+			if owner, ok := c.owners[goroutineID()]; ok {
+				index = owner
+			}
+		}
+	}
+	c.indices = append(c.indices, index)
+}
+
+// finishCollecting sorts the errors recorded by the registered cleanups in order of registration.
+func (g *Goalie) finishCollecting() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c := g.collecting
+	g.collecting = nil
+
+	type indexedErr struct {
+		index int
+		err   error
+	}
+	errs := make([]indexedErr, len(c.indices))
+	for k, index := range c.indices {
+		errs[k] = indexedErr{index, g.errs[c.base+k]}
+	}
+	slices.SortStableFunc(errs, func(a, b indexedErr) int {
+		return cmp.Compare(a.index, b.index)
+	})
+	for k, e := range errs {
+		g.errs[c.base+k] = e.err
+	}
+}
+
+// runConcurrently runs cleanups concurrently and records their errors,
+// where offset is the registration index of the first cleanup.
+// If a cleanup panics, the panic is re-raised in the calling goroutine after all cleanups finish.
+func (g *Goalie) runConcurrently(cleanups []cleanup, offset int, primary error) {
+	maxConcurrency := g.maxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultMaxConcurrency
+	}
+
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	var panicValue any
+	panicked := false
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		c := cleanups[i]
+		index := offset + i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			defer func() {
+				// A panic cannot be recovered by the caller in another goroutine.
+				if r := recover(); r != nil {
+					panicOnce.Do(func() {
+						panicValue = r
+						panicked = true
+					})
+				}
+			}()
+
+			// Let Guard calls inside the cleanup find which cleanup they belong to.
+			id := goroutineID()
+			g.mu.Lock()
+			g.collecting.owners[id] = index
+			g.mu.Unlock()
+			defer func() {
+				g.mu.Lock()
+				delete(g.collecting.owners, id)
+				g.mu.Unlock()
+			}()
+
+			if err := g.capture(origin{label: c.label, site: c.site}, func() error {
+				return c.fn(primary)
+			}); err != nil {
+				g.mu.Lock()
+				g.appendErr(err, index)
+				g.mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if panicked {
+		panic(panicValue)
+	}
+}
+
+// goroutineID returns the ID of the current goroutine, parsed from the header of its stack trace,
+// e.g. "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	field, _, _ := strings.Cut(strings.TrimPrefix(string(buf[:n]), "goroutine "), " ")
+	id, _ := strconv.ParseUint(field, 10, 64)
+
+	return id
+}
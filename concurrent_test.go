@@ -0,0 +1,164 @@
+package goalie_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_DeferConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	cleanup := func(name string, delay time.Duration) func() error {
+		return func() error {
+			time.Sleep(delay)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return errors.New(name)
+		}
+	}
+
+	err := func() (err error) {
+		g := goalie.New(goalie.WithMaxConcurrency(2))
+		defer g.Collect(&err)
+
+		g.Defer(cleanup("a", 0))
+		g.DeferConcurrent(cleanup("b", 0))
+		// c finishes after b, but its error must come after b's.
+		g.DeferConcurrent(cleanup("c", 20*time.Millisecond))
+		g.Defer(cleanup("d", 0))
+
+		return nil
+	}()
+
+	assert(t, 4, len(order))
+	assert(t, "d", order[0])
+	assert(t, "b", order[1])
+	assert(t, "c", order[2])
+	assert(t, "a", order[3])
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, 4, len(gerr.Cleanups))
+	for i, name := range []string{"a", "b", "c", "d"} {
+		assert(t, name, gerr.Cleanups[i].Err.Error())
+	}
+}
+
+func Test_WithMaxConcurrency(t *testing.T) {
+	type testcase struct {
+		maxConcurrency int
+		cleanups       int
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var running, maxRunning atomic.Int32
+		err := func() (err error) {
+			g := goalie.New(goalie.WithMaxConcurrency(tc.maxConcurrency))
+			defer g.Collect(&err)
+
+			for i := range tc.cleanups {
+				g.DeferConcurrent(func() error {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+					return fmt.Errorf("cleanup %d", i)
+				})
+			}
+
+			return nil
+		}()
+
+		var gerr *goalie.Error
+		assert(t, true, errors.As(err, &gerr))
+		assert(t, tc.cleanups, len(gerr.Cleanups))
+		assert(t, true, int(maxRunning.Load()) <= tc.maxConcurrency)
+		for i, c := range gerr.Cleanups {
+			assert(t, fmt.Sprintf("cleanup %d", i), c.Err.Error())
+		}
+	}
+
+	testcases := map[string]testcase{
+		"sequential": {
+			maxConcurrency: 1,
+			cleanups:       4,
+		},
+		"bounded": {
+			maxConcurrency: 3,
+			cleanups:       10,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_DeferConcurrent_Panic(t *testing.T) {
+	var recovered any
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+
+		var err error
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		g.DeferConcurrent(func() error {
+			return nil
+		})
+		g.DeferConcurrent(func() error {
+			panic("cleanup panic")
+		})
+	}()
+	assert(t, "cleanup panic", recovered)
+}
+
+func Test_DeferConcurrent_NestedGuard(t *testing.T) {
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		g.DeferConcurrent(func() error {
+			// This guard captures its error after b's, but it must come first.
+			time.Sleep(20 * time.Millisecond)
+			g.Guard(func() error { return errors.New("a") })
+			return nil
+		})
+		g.DeferConcurrent(func() error {
+			g.Guard(func() error { return errors.New("b") })
+			return errors.New("b2")
+		})
+		g.Defer(func() error {
+			g.Guard(func() error { return errors.New("c") })
+			return nil
+		})
+
+		return nil
+	}()
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, 4, len(gerr.Cleanups))
+	for i, name := range []string{"a", "b", "b2", "c"} {
+		assert(t, name, gerr.Cleanups[i].Err.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package goalie
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries g.
+//
+// This lets lower layers of a deep call stack guard or register cleanups
+// into the Goalie of the caller, e.g. the Goalie of a request.
+//
+// Example:
+//
+//	func handle(ctx context.Context) (err error) {
+//		g := goalie.New()
+//		defer g.Collect(&err)
+//
+//		return repository.Find(goalie.NewContext(ctx, g))
+//	}
+func NewContext(ctx context.Context, g *Goalie) context.Context {
+	return context.WithValue(ctx, contextKey{}, g)
+}
+
+// FromContext returns the Goalie carried by ctx, which is the innermost one set by [NewContext].
+//
+// If ctx carries no Goalie, FromContext returns nil and false,
+// so the caller can fall back to handling its cleanups by itself.
+//
+// Example:
+//
+//	func Find(ctx context.Context) error {
+//		f, err := os.Open(path)
+//		if err != nil {
+//			return err
+//		}
+//		if g, ok := goalie.FromContext(ctx); ok {
+//			g.Defer(f.Close)
+//		} else {
+//			defer f.Close()
+//		}
+//
+//		// ... do something ...
+//		return nil
+//	}
+func FromContext(ctx context.Context) (*Goalie, bool) {
+	g, ok := ctx.Value(contextKey{}).(*Goalie)
+	return g, ok && g != nil
+}
@@ -0,0 +1,103 @@
+package goalie_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_FromContext(t *testing.T) {
+	outer := goalie.New()
+	inner := goalie.New()
+
+	type testcase struct {
+		ctx      context.Context
+		expected *goalie.Goalie
+		isFound  bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		g, ok := goalie.FromContext(tc.ctx)
+		assert(t, tc.isFound, ok)
+		assert(t, tc.expected, g)
+	}
+
+	testcases := map[string]testcase{
+		"no goalie": {
+			ctx:      context.Background(),
+			expected: nil,
+			isFound:  false,
+		},
+		"nil goalie": {
+			ctx:      goalie.NewContext(context.Background(), nil),
+			expected: nil,
+			isFound:  false,
+		},
+		"goalie": {
+			ctx:      goalie.NewContext(context.Background(), outer),
+			expected: outer,
+			isFound:  true,
+		},
+		"nested goalie": {
+			ctx:      goalie.NewContext(goalie.NewContext(context.Background(), outer), inner),
+			expected: inner,
+			isFound:  true,
+		},
+		"derived context": {
+			ctx:      context.WithValue(goalie.NewContext(context.Background(), outer), struct{}{}, "value"),
+			expected: outer,
+			isFound:  true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_FromContext_Concurrent(t *testing.T) {
+	const n = 50
+
+	// storage simulates a lower layer registering its cleanups into the Goalie of the caller.
+	storage := func(ctx context.Context, i int) {
+		g, ok := goalie.FromContext(ctx)
+		if !ok {
+			return
+		}
+		if i%2 == 0 {
+			g.Defer(func() error { return fmt.Errorf("deferred %d", i) })
+		} else {
+			g.Guard(func() error { return fmt.Errorf("guarded %d", i) })
+		}
+	}
+
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		ctx := goalie.NewContext(context.Background(), g)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				storage(ctx, i)
+			}()
+		}
+		wg.Wait()
+
+		return nil
+	}()
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, n, len(gerr.Cleanups))
+}
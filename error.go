@@ -0,0 +1,135 @@
+package goalie
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CleanupError is an error captured from a cleanup function by [Goalie.Guard].
+type CleanupError struct {
+	// Label describes the cleanup, e.g. "os.(*File).Close".
+	Label string
+	// Site is the "file:line" location in the caller's code where the cleanup was guarded,
+	// e.g. by [Goalie.Guard], or where it was registered to run in [Goalie.Collect], e.g. by [Goalie.Defer].
+	// For a deferred [Goalie.Guard], this is where the surrounding function returned.
+	Site string
+	// Duration is how long the cleanup took.
+	Duration time.Duration
+	// Err is the error returned by the cleanup, wrapped by the [WrapErrorFunc].
+	Err error
+}
+
+func (e *CleanupError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CleanupError) Unwrap() error {
+	return e.Err
+}
+
+// Error is the error returned by [Goalie.Collect] when using the default [JoinErrorsFunc].
+//
+// It formats as a compact message with %v, the same as [errors.Join],
+// and as an indented tree with %+v:
+//
+//	internal error
+//	cleanup errors (1):
+//	  [1] os.(*File).Close at /path/to/main.go:42 (took 12µs)
+//	      close go.mod: file already closed
+//	      caused by: file already closed
+type Error struct {
+	// Primary is the error returned by the function itself, or nil.
+	Primary error
+	// Cleanups are the errors captured from cleanup functions, in capture order.
+	Cleanups []*CleanupError
+}
+
+// Join joins errs into an [*Error].
+// A [*CleanupError] is treated as a cleanup error and any other error as the primary error.
+// Nil errors are discarded, and Join returns nil if every error is nil.
+func Join(errs ...error) error {
+	var primaries []error
+	var cleanups []*CleanupError
+	for _, err := range errs {
+		switch err := err.(type) {
+		case nil:
+		case *CleanupError:
+			cleanups = append(cleanups, err)
+		default:
+			primaries = append(primaries, err)
+		}
+	}
+
+	if len(primaries) == 0 && len(cleanups) == 0 {
+		return nil
+	}
+
+	e := &Error{Cleanups: cleanups}
+	if len(primaries) == 1 {
+		e.Primary = primaries[0]
+	} else if len(primaries) > 1 {
+		e.Primary = errors.Join(primaries...)
+	}
+
+	return e
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	for i, err := range e.Unwrap() {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, len(e.Cleanups)+1)
+	if e.Primary != nil {
+		errs = append(errs, e.Primary)
+	}
+	for _, c := range e.Cleanups {
+		errs = append(errs, c)
+	}
+
+	return errs
+}
+
+// Format implements [fmt.Formatter].
+// The %+v verb prints the primary error followed by a tree of cleanup errors;
+// other verbs print the same as [Error.Error].
+func (e *Error) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		_, _ = io.WriteString(s, e.Error())
+		return
+	}
+
+	if e.Primary != nil {
+		_, _ = fmt.Fprintf(s, "%+v\n", e.Primary)
+	}
+
+	_, _ = fmt.Fprintf(s, "cleanup errors (%d):", len(e.Cleanups))
+	for i, c := range e.Cleanups {
+		_, _ = fmt.Fprintf(s, "\n  [%d] %s", i+1, c.Label)
+		if c.Site != "" {
+			_, _ = fmt.Fprintf(s, " at %s", c.Site)
+		}
+		_, _ = fmt.Fprintf(s, " (took %s)", c.Duration)
+		_, _ = fmt.Fprintf(s, "\n%s", indent(fmt.Sprintf("%+v", c.Err), "      "))
+
+		for cause := errors.Unwrap(c.Err); cause != nil; cause = errors.Unwrap(cause) {
+			_, _ = fmt.Fprintf(s, "\n%s", indent(fmt.Sprintf("caused by: %+v", cause), "      "))
+		}
+	}
+}
+
+// indent prefixes every line of s with prefix.
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,108 @@
+package goalie_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Error(t *testing.T) {
+	_, err := countLines("goalie_test.go")
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, errInternal, gerr.Primary)
+	assert(t, 1, len(gerr.Cleanups))
+	assert(t, "os.(*File).Close", gerr.Cleanups[0].Label)
+	assert(t, true, strings.Contains(gerr.Cleanups[0].Site, "goalie_test.go:"))
+	assert(t, true, errors.Is(gerr.Cleanups[0], os.ErrClosed))
+}
+
+func Test_Error_Format(t *testing.T) {
+	errCause := errors.New("cause")
+	errNested := goalie.Join(errors.New("nested primary"), &goalie.CleanupError{
+		Label: "nested",
+		Err:   errors.New("nested cleanup"),
+	})
+
+	type testcase struct {
+		err      error
+		format   string
+		expected string
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		assert(t, tc.expected, fmt.Sprintf(tc.format, tc.err))
+	}
+
+	testcases := map[string]testcase{
+		"compact with %v": {
+			err: goalie.Join(errInternal, &goalie.CleanupError{
+				Label: "close",
+				Err:   fmt.Errorf("close: %w", errCause),
+			}),
+			format:   "%v",
+			expected: "internal error\nclose: cause",
+		},
+		"compact with %s": {
+			err: goalie.Join(&goalie.CleanupError{
+				Label: "close",
+				Err:   errCause,
+			}),
+			format:   "%s",
+			expected: "cause",
+		},
+		"tree with %+v": {
+			err: goalie.Join(errInternal, &goalie.CleanupError{
+				Label: "close",
+				Site:  "main.go:42",
+				Err:   fmt.Errorf("close: %w", errCause),
+			}),
+			format: "%+v",
+			expected: "internal error\n" +
+				"cleanup errors (1):\n" +
+				"  [1] close at main.go:42 (took 0s)\n" +
+				"      close: cause\n" +
+				"      caused by: cause",
+		},
+		"nested tree with %+v": {
+			err: goalie.Join(&goalie.CleanupError{
+				Label: "outer",
+				Err:   errNested,
+			}),
+			format: "%+v",
+			expected: "cleanup errors (1):\n" +
+				"  [1] outer (took 0s)\n" +
+				"      nested primary\n" +
+				"      cleanup errors (1):\n" +
+				"        [1] nested (took 0s)\n" +
+				"            nested cleanup",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Join(t *testing.T) {
+	assert(t, nil, goalie.Join())
+	assert(t, nil, goalie.Join(nil, nil))
+
+	errA, errB := errors.New("a"), errors.New("b")
+	err := goalie.Join(errA, errB)
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, 0, len(gerr.Cleanups))
+	assert(t, true, errors.Is(err, errA))
+	assert(t, true, errors.Is(err, errB))
+}
@@ -0,0 +1,125 @@
+// Package execgoalie provides os/exec helpers for Goalie.
+package execgoalie
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+// ErrKilled is reported when a process does not stop within the timeout after the signal and is killed.
+var ErrKilled = errors.New("process was killed because it did not stop in time")
+
+// DefaultTimeout is the default time to wait for a process to stop after the signal.
+const DefaultTimeout = 5 * time.Second
+
+// Option is a function that configures [DeferCmd].
+type Option func(*config)
+
+type config struct {
+	signal  os.Signal
+	timeout time.Duration
+}
+
+// WithSignal sets the signal sent to a process still running at collection time.
+// By default, [os.Interrupt] is used.
+func WithSignal(signal os.Signal) Option {
+	return func(c *config) {
+		c.signal = signal
+	}
+}
+
+// WithTimeout sets how long to wait for a process to stop after the signal before killing it.
+// By default, [DefaultTimeout] is used.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// DeferCmd registers the started cmd to be stopped and waited for when [goalie.Goalie.Collect] is called,
+// so that it never leaks as a zombie process.
+//
+// If cmd is still running, it is sent the signal and killed if it does not stop within the timeout.
+// The error of cmd.Wait is captured with the command line as its label,
+// except that a process terminated by the signal is considered successful.
+// Nothing is captured if cmd has already been waited for.
+//
+// Example:
+//
+//	cmd := exec.Command("server", "--port", "8080")
+//	if err := cmd.Start(); err != nil {
+//		return err
+//	}
+//	execgoalie.DeferCmd(g, cmd, execgoalie.WithSignal(syscall.SIGTERM))
+func DeferCmd(g *goalie.Goalie, cmd *exec.Cmd, options ...Option) {
+	c := config{
+		signal:  os.Interrupt,
+		timeout: DefaultTimeout,
+	}
+	for _, o := range options {
+		o(&c)
+	}
+
+	g.DeferLabel(cmd.String(), func() error {
+		return stop(cmd, c)
+	})
+}
+
+// stop stops cmd if it is still running and waits for it.
+func stop(cmd *exec.Cmd, c config) error {
+	if cmd.Process == nil || cmd.ProcessState != nil {
+		// cmd is not started, or already waited for by the caller, who received its error.
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	default:
+	}
+
+	if err := cmd.Process.Signal(c.signal); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			// The process exited by itself and was reaped after the check above.
+			return <-done
+		}
+		// The signal may be unsupported, e.g. os.Interrupt on Windows.
+		return kill(cmd, done, fmt.Errorf("%w: failed to send %s: %w", ErrKilled, c.signal, err))
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && terminatedBy(exitErr, c.signal) {
+			// The process is terminated by the signal as requested.
+			return nil
+		}
+
+		return err
+	case <-timer.C:
+		return kill(cmd, done, fmt.Errorf("%w: did not stop within %s after %s", ErrKilled, c.timeout, c.signal))
+	}
+}
+
+// kill kills cmd and waits for it, returning reason unless killing fails.
+func kill(cmd *exec.Cmd, done <-chan error, reason error) error {
+	if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return errors.Join(reason, err)
+	}
+	<-done
+
+	return reason
+}
@@ -0,0 +1,143 @@
+package execgoalie_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+	"github.com/ras0q/goalie/execgoalie"
+)
+
+func assert[T comparable](t *testing.T, expected, got T) {
+	t.Helper()
+	if got != expected {
+		t.Fatalf("assertion failed (expected: %+v, got: %+v)", expected, got)
+	}
+}
+
+const helperEnv = "EXECGOALIE_HELPER"
+
+// TestMain runs the test binary as a helper process when helperEnv is set.
+func TestMain(m *testing.M) {
+	switch os.Getenv(helperEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "exit":
+		os.Exit(3)
+	case "sleep":
+		time.Sleep(time.Minute)
+	case "ignore":
+		signal.Ignore(os.Interrupt)
+		time.Sleep(time.Minute)
+	}
+}
+
+func Test_DeferCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt is not supported on Windows")
+	}
+
+	type testcase struct {
+		mode string
+		// before is called with the running cmd before collection.
+		before        func(t *testing.T, cmd *exec.Cmd)
+		isExitError   bool
+		isKilledError bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		cmd := exec.Command(os.Args[0], "-test.run=^$", tc.mode)
+		cmd.Env = append(os.Environ(), helperEnv+"="+tc.mode)
+
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+
+			if err := cmd.Start(); err != nil {
+				return err
+			}
+			execgoalie.DeferCmd(g, cmd, execgoalie.WithTimeout(500*time.Millisecond))
+
+			if tc.mode == "exit" {
+				// Give the process time to exit by itself.
+				time.Sleep(500 * time.Millisecond)
+			} else {
+				// Give the process time to set up its signal handler.
+				time.Sleep(100 * time.Millisecond)
+			}
+			if tc.before != nil {
+				tc.before(t, cmd)
+			}
+
+			return nil
+		}()
+		assert(t, true, cmd.ProcessState != nil)
+
+		var exitErr *exec.ExitError
+		assert(t, tc.isExitError, errors.As(err, &exitErr))
+		assert(t, tc.isKilledError, errors.Is(err, execgoalie.ErrKilled))
+		assert(t, tc.isExitError || tc.isKilledError, err != nil)
+
+		if err != nil {
+			var gerr *goalie.Error
+			assert(t, true, errors.As(err, &gerr))
+			assert(t, true, strings.HasSuffix(gerr.Cleanups[0].Label, tc.mode))
+		}
+	}
+
+	testcases := map[string]testcase{
+		"record exit error": {
+			mode:        "exit",
+			isExitError: true,
+		},
+		"stop with signal": {
+			mode: "sleep",
+		},
+		"already waited for": {
+			mode: "exit",
+			before: func(t *testing.T, cmd *exec.Cmd) {
+				var exitErr *exec.ExitError
+				assert(t, true, errors.As(cmd.Wait(), &exitErr))
+			},
+		},
+		"report process killed before collection": {
+			mode: "sleep",
+			before: func(t *testing.T, cmd *exec.Cmd) {
+				// Simulate a crash, e.g. by the OOM killer.
+				assert(t, nil, cmd.Process.Kill())
+				time.Sleep(100 * time.Millisecond)
+			},
+			isExitError: true,
+		},
+		"kill after timeout": {
+			mode:          "ignore",
+			isKilledError: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_DeferCmd_NotStarted(t *testing.T) {
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		execgoalie.DeferCmd(g, exec.Command("never-started"))
+
+		return nil
+	}()
+	assert(t, nil, err)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package execgoalie
+
+import (
+	"os"
+	"os/exec"
+)
+
+// terminatedBy reports whether the process of exitErr was terminated by signal.
+// Signals other than Kill cannot be sent on this platform, so it always reports false.
+func terminatedBy(exitErr *exec.ExitError, signal os.Signal) bool {
+	return false
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package execgoalie
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// terminatedBy reports whether the process of exitErr was terminated by signal.
+func terminatedBy(exitErr *exec.ExitError, signal os.Signal) bool {
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok {
+		return false
+	}
+	sig, ok := signal.(syscall.Signal)
+	if !ok {
+		return false
+	}
+
+	return status.Signaled() && status.Signal() == sig
+}
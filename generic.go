@@ -0,0 +1,42 @@
+package goalie
+
+// Guard1 executes errFunc with the argument a and captures any error returned, like [Goalie.Guard].
+//
+// Because a is bound when the defer statement is evaluated,
+// this has the same argument evaluation semantics as a plain `defer errFunc(a)`.
+//
+// Example:
+//
+//	defer goalie.Guard1(g, os.Remove, tmpPath)
+func Guard1[A any](g *Goalie, errFunc func(A) error, a A) {
+	g.guard(origin{labelFunc: errFunc}, func() error {
+		return errFunc(a)
+	})
+}
+
+// Guard2 executes errFunc with the arguments a and b and captures any error returned, like [Goalie.Guard].
+//
+// Because a and b are bound when the defer statement is evaluated,
+// this has the same argument evaluation semantics as a plain `defer errFunc(a, b)`.
+//
+// Example:
+//
+//	defer goalie.Guard2(g, os.Rename, tmpPath, path)
+func Guard2[A, B any](g *Goalie, errFunc func(A, B) error, a A, b B) {
+	g.guard(origin{labelFunc: errFunc}, func() error {
+		return errFunc(a, b)
+	})
+}
+
+// GuardResult executes resultFunc and captures any error returned, like [Goalie.Guard].
+// The non-error result is discarded.
+//
+// Example:
+//
+//	defer goalie.GuardResult(g, cmd.Output)
+func GuardResult[T any](g *Goalie, resultFunc func() (T, error)) {
+	g.guard(origin{labelFunc: resultFunc}, func() error {
+		_, err := resultFunc()
+		return err
+	})
+}
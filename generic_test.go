@@ -0,0 +1,47 @@
+package goalie_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_GuardN(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errResult := errors.New("result")
+
+	// The arguments are changed after defer to check that they are bound at defer time.
+	guardAll := func() (args []string, err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		a, b := "a", "b"
+		defer goalie.Guard1(g, func(a string) error {
+			args = append(args, a)
+			return fmt.Errorf("%w: %s", errA, a)
+		}, a)
+		defer goalie.Guard2(g, func(a string, b string) error {
+			args = append(args, a+b)
+			return fmt.Errorf("%w: %s%s", errB, a, b)
+		}, a, b)
+		defer goalie.GuardResult(g, func() (int, error) {
+			args = append(args, "result")
+			return 1, errResult
+		})
+		a, b = "changed", "changed"
+
+		return nil, nil
+	}
+
+	args, err := guardAll()
+	assert(t, 3, len(args))
+	assert(t, "result", args[0])
+	assert(t, "ab", args[1])
+	assert(t, "a", args[2])
+	assert(t, true, errors.Is(err, errA))
+	assert(t, true, errors.Is(err, errB))
+	assert(t, true, errors.Is(err, errResult))
+}
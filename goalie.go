@@ -4,13 +4,34 @@ package goalie
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Goalie is the main struct that manages captured error.
+// It is safe for concurrent use, so cleanups can be guarded or registered from multiple goroutines.
 type Goalie struct {
-	errs           []error
+	mu       sync.Mutex
+	errs     []error
+	cleanups []cleanup
+	records  []Record
+	// collecting is set while Collect runs the registered cleanups.
+	collecting *collection
+
 	wrapErrorFunc  WrapErrorFunc
 	joinErrorsFunc JoinErrorsFunc
+	typedNilPolicy TypedNilPolicy
+	maxConcurrency int
+
+	clock           func() time.Time
+	slowThreshold   time.Duration
+	slowCleanupFunc SlowCleanupFunc
+
+	recording bool
 }
 
 // New creates a new Goalie instance.
@@ -27,6 +48,16 @@ func New(options ...Option) *Goalie {
 // Collect captures all errors collected by Goalie and joins them into a single error,
 // assigning it to `errp` (a pointer to the function's return error variable).
 //
+// Cleanups registered with [Goalie.Defer] and [Goalie.DeferConcurrent] are run first,
+// in reverse order of registration.
+// Since Collect is deferred first, they run after every deferred [Goalie.Guard],
+// whenever they were registered.
+//
+// Errors captured by Guard calls before Collect are reported first, in the order they were captured,
+// which is the reverse order of the defer statements for deferred Guards.
+// They are followed by the errors of the registered cleanups in order of registration,
+// including those captured by Guard calls inside them.
+//
 // Use this method in a `defer` statement at the top of a function to ensure
 // all errors are collected and propagated before the function returns.
 //
@@ -41,15 +72,22 @@ func New(options ...Option) *Goalie {
 //		return nil
 //	}
 func (g *Goalie) Collect(errp *error) {
-	if len(g.errs) == 0 {
+	g.runCleanups(*errp)
+
+	g.mu.Lock()
+	captured := g.errs
+	g.errs = nil
+	g.mu.Unlock()
+
+	if len(captured) == 0 {
 		return
 	}
 
-	errs := make([]error, 0, len(g.errs)+1)
+	errs := make([]error, 0, len(captured)+1)
 	if *errp != nil {
 		errs = append(errs, *errp)
 	}
-	errs = append(errs, g.errs...)
+	errs = append(errs, captured...)
 
 	joinErrorsFunc := g.joinErrorsFunc
 	if g.joinErrorsFunc == nil {
@@ -68,18 +106,234 @@ func (g *Goalie) Collect(errp *error) {
 //	file, _ := os.Open("somefile.txt")
 //	defer g.Guard(file.Close)
 func (g *Goalie) Guard(errFunc func() error) {
-	if err := errFunc(); err != nil {
+	g.guard(origin{labelFunc: errFunc}, errFunc)
+}
+
+// GuardLabel is like [Goalie.Guard] but labels the captured error with label instead of the function name.
+//
+// Example:
+//
+//	defer g.GuardLabel("close config file", file.Close)
+func (g *Goalie) GuardLabel(label string, errFunc func() error) {
+	g.guard(origin{label: label}, errFunc)
+}
+
+// Defer registers errFunc to be guarded when [Goalie.Collect] is called.
+//
+// Unlike `defer g.Guard(errFunc)`, the cleanup runs inside Collect,
+// which lets helpers such as [Acquire] register cleanups on behalf of the caller.
+// Note that it runs after all deferred Guards, even those deferred before it was registered,
+// so a resource that errFunc depends on must also be released with Defer rather than a deferred Guard.
+//
+// Example:
+//
+//	g.Defer(file.Close)
+func (g *Goalie) Defer(errFunc func() error) {
+	g.deferCleanup(cleanup{
+		label: funcLabel(errFunc),
+		site:  callerSite(),
+		fn: func(error) error {
+			return errFunc()
+		},
+	})
+}
+
+// DeferLabel is like [Goalie.Defer] but labels the captured error with label instead of the function name.
+func (g *Goalie) DeferLabel(label string, errFunc func() error) {
+	g.deferCleanup(cleanup{
+		label: label,
+		site:  callerSite(),
+		fn: func(error) error {
+			return errFunc()
+		},
+	})
+}
+
+// cleanup is a cleanup registered to run in [Goalie.Collect].
+type cleanup struct {
+	label string
+	site  string
+	// fn receives the primary error of the enclosing function.
+	fn func(primary error) error
+	// concurrent reports whether fn may run concurrently with adjacent concurrent cleanups.
+	concurrent bool
+}
+
+// deferCleanup registers c to run in [Goalie.Collect].
+func (g *Goalie) deferCleanup(c cleanup) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.cleanups = append(g.cleanups, c)
+}
+
+// origin identifies a cleanup by its label and site.
+// Resolving them needs reflection and a stack walk, so it is deferred by [origin.resolve]
+// until the cleanup fails, is slow or is recorded.
+type origin struct {
+	label string
+	// labelFunc is the function whose name is the label if label is empty.
+	labelFunc any
+	// site is where the cleanup was registered, or empty for the caller outside of goalie.
+	site string
+}
+
+// resolve returns the label and site of o.
+// It must be called from the goroutine running the guard, so that the caller can be found.
+func (o origin) resolve() (label, site string) {
+	label, site = o.label, o.site
+	if label == "" && o.labelFunc != nil {
+		label = funcLabel(o.labelFunc)
+	}
+	if site == "" {
+		site = callerSite()
+	}
+
+	return label, site
+}
+
+// guard runs errFunc and records its error as a [CleanupError] from o.
+func (g *Goalie) guard(o origin, errFunc func() error) {
+	if err := g.capture(o, errFunc); err != nil {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		g.appendErr(err, -1)
+	}
+}
+
+// capture runs errFunc and returns its error as a [CleanupError] from o,
+// or nil if errFunc succeeds.
+// Unlike guard, it does not record the error, so it can be called concurrently.
+func (g *Goalie) capture(o origin, errFunc func() error) *CleanupError {
+	now := g.now()
+	start := now()
+	err := errFunc()
+	duration := now().Sub(start)
+
+	if err != nil && isTypedNil(err) {
+		switch g.typedNilPolicy {
+		case TypedNilAsSuccess:
+			err = nil
+		case TypedNilAsMisuse:
+			err = fmt.Errorf("%w: %T", ErrTypedNil, err)
+		}
+	}
+
+	if err == nil && !g.recording && !g.isSlow(duration) {
+		// Fast path: the label and site are not needed.
+		return nil
+	}
+	label, site := o.resolve()
+
+	if warning := g.checkSlow(label, site, duration); warning != nil {
+		if err == nil {
+			err = warning
+		} else {
+			err = errors.Join(err, warning)
+		}
+	}
+
+	if err != nil {
 		wrapErrorFunc := g.wrapErrorFunc
 		if wrapErrorFunc == nil {
 			wrapErrorFunc = fallbackWrapErrorFunc
 		}
 
 		err = wrapErrorFunc(err)
+	}
 
-		g.errs = append(g.errs, err)
+	g.record(Record{
+		Label:    label,
+		Site:     site,
+		Duration: duration,
+		Err:      err,
+	})
+
+	if err == nil {
+		return nil
+	}
+
+	return &CleanupError{
+		Label:    label,
+		Site:     site,
+		Duration: duration,
+		Err:      err,
 	}
 }
 
+// funcLabel returns the name of the function f, e.g. "os.(*File).Close".
+func funcLabel(f any) string {
+	return pcLabel(reflect.ValueOf(f).Pointer())
+}
+
+// methodLabel returns the name of the method of v in the same format as [funcLabel],
+// even if v is held in an interface, e.g. "os.(*File).Close".
+func methodLabel(v any, method string) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return method
+	}
+	// Prefer the method declared on the value receiver, as a method value of a pointer does.
+	if t.Kind() == reflect.Pointer {
+		if m, ok := t.Elem().MethodByName(method); ok {
+			return pcLabel(m.Func.Pointer())
+		}
+	}
+	if m, ok := t.MethodByName(method); ok {
+		return pcLabel(m.Func.Pointer())
+	}
+
+	return fmt.Sprintf("(%T).%s", v, method)
+}
+
+// pcLabel returns the name of the function at pc, without the suffix of method values.
+func pcLabel(pc uintptr) string {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(fn.Name(), "-fm")
+}
+
+// callerSite returns the "file:line" location of the nearest caller outside of goalie and its subpackages,
+// so that helpers built on Goalie report the location in the user's code.
+func callerSite() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isGoalieFunc(frame.Function) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isGoalieFunc reports whether the function named name belongs to goalie or its subpackages,
+// excluding their external test packages.
+func isGoalieFunc(name string) bool {
+	const modulePath = "github.com/ras0q/goalie"
+	rest, ok := strings.CutPrefix(name, modulePath)
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(rest, ".") {
+		return true
+	}
+	if !strings.HasPrefix(rest, "/") {
+		return false
+	}
+
+	pkg := rest[strings.LastIndex(rest, "/")+1:]
+	pkg, _, _ = strings.Cut(pkg, ".")
+	return !strings.HasSuffix(pkg, "_test")
+}
+
 // Option is a function that configures a [Goalie] instance.
 type Option func(*Goalie)
 
@@ -112,7 +366,7 @@ func SetFallbackWrapErrorFunc(wrapErrorFunc WrapErrorFunc) error {
 }
 
 // JoinErrorsFunc is a function type for joining multiple errors into a single error.
-// By default, Goalie uses [errors.Join].
+// By default, Goalie uses [Join].
 type JoinErrorsFunc func(...error) error
 
 // WithJoinErrorsFunc sets the function used to join errors.
@@ -126,7 +380,7 @@ func WithJoinErrorsFunc(joinErrorsFunc JoinErrorsFunc) Option {
 	}
 }
 
-var fallbackJoinErrorsFunc JoinErrorsFunc = errors.Join
+var fallbackJoinErrorsFunc JoinErrorsFunc = Join
 
 // SetFallbackJoinErrorsFunc sets the fallback function used to join multiple errors.
 // This function is used when no custom join function is provided to a Goalie instance.
@@ -166,7 +166,7 @@ func Test_SetFallbackJoinErrorsFunc(t *testing.T) {
 			err := goalie.SetFallbackJoinErrorsFunc(tc.joinErrorsFunc)
 			assert(t, tc.isSetFallbackJoinErrorsFuncError, err != nil)
 			t.Cleanup(func() {
-				err := goalie.SetFallbackJoinErrorsFunc(errors.Join)
+				err := goalie.SetFallbackJoinErrorsFunc(goalie.Join)
 				assert(t, nil, err)
 			})
 
@@ -217,3 +217,70 @@ func Test_SetFallbackJoinErrorsFunc(t *testing.T) {
 		})
 	}
 }
+
+func Test_Defer(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	var order []string
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		g.Defer(func() error {
+			order = append(order, "first")
+			return errFirst
+		})
+		g.Defer(func() error {
+			order = append(order, "second")
+			return errSecond
+		})
+
+		return errInternal
+	}()
+
+	assert(t, 2, len(order))
+	assert(t, "second", order[0])
+	assert(t, "first", order[1])
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, errInternal, gerr.Primary)
+	assert(t, 2, len(gerr.Cleanups))
+	// Errors are reported in order of registration.
+	assert(t, errFirst, gerr.Cleanups[0].Err)
+	assert(t, errSecond, gerr.Cleanups[1].Err)
+}
+
+func succeed() error {
+	return nil
+}
+
+func Benchmark_Guard(b *testing.B) {
+	b.ReportAllocs()
+
+	g := goalie.New()
+	b.ResetTimer()
+	for range b.N {
+		g.Guard(succeed)
+	}
+}
+
+func Benchmark_GuardLabel(b *testing.B) {
+	b.ReportAllocs()
+
+	g := goalie.New()
+	b.ResetTimer()
+	for range b.N {
+		g.GuardLabel("succeed", succeed)
+	}
+}
+
+func Test_Guard_NoAllocs(t *testing.T) {
+	g := goalie.New()
+	allocs := testing.AllocsPerRun(100, func() {
+		g.Guard(succeed)
+	})
+	// A successful Guard must not resolve its label and site.
+	assert(t, 0.0, allocs)
+}
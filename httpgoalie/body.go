@@ -0,0 +1,77 @@
+package httpgoalie
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ras0q/goalie"
+)
+
+// DefaultDrainLimit is the default maximum number of bytes drained by [GuardBody].
+const DefaultDrainLimit = 64 << 10
+
+// BodyOption is a function that configures [GuardBody].
+type BodyOption func(*bodyConfig)
+
+type bodyConfig struct {
+	drainLimit int64
+}
+
+// WithDrainLimit sets the maximum number of bytes drained from a response body before closing it.
+// If more bytes remain, the body is closed without draining the rest,
+// and the connection is not reused.
+func WithDrainLimit(n int64) BodyOption {
+	return func(c *bodyConfig) {
+		c.drainLimit = n
+	}
+}
+
+// GuardBody drains and closes the body of resp, capturing both errors with g.
+//
+// Draining the body before closing it lets the [http.Transport] reuse the connection.
+// The errors are labelled with the request method and URL, without its password and query.
+// Draining stops after [DefaultDrainLimit] bytes unless [WithDrainLimit] is set.
+//
+// Example:
+//
+//	resp, err := http.Get(url)
+//	if err != nil {
+//		return err
+//	}
+//	defer httpgoalie.GuardBody(g, resp)
+func GuardBody(g *goalie.Goalie, resp *http.Response, options ...BodyOption) {
+	c := bodyConfig{
+		drainLimit: DefaultDrainLimit,
+	}
+	for _, o := range options {
+		o(&c)
+	}
+
+	target := "response body"
+	if resp.Request != nil && resp.Request.URL != nil {
+		target = fmt.Sprintf("response body of %s %s", resp.Request.Method, labelURL(resp.Request.URL))
+	}
+
+	g.GuardLabel("drain "+target, func() error {
+		_, err := io.CopyN(io.Discard, resp.Body, c.drainLimit)
+		if err == io.EOF {
+			return nil
+		}
+
+		return err
+	})
+	g.GuardLabel("close "+target, resp.Body.Close)
+}
+
+// labelURL returns u without its password, query and fragment, which may contain credentials.
+func labelURL(u *url.URL) string {
+	u2 := *u
+	u2.RawQuery = ""
+	u2.ForceQuery = false
+	u2.Fragment = ""
+	u2.RawFragment = ""
+
+	return u2.Redacted()
+}
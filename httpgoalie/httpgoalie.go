@@ -0,0 +1,138 @@
+// Package httpgoalie provides net/http helpers for Goalie.
+package httpgoalie
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/ras0q/goalie"
+)
+
+// Reporter is a function type for reporting the cleanup errors collected for a request.
+type Reporter func(r *http.Request, err error)
+
+// Option is a function that configures [Middleware].
+type Option func(*config)
+
+type config struct {
+	options             []goalie.Option
+	reporter            Reporter
+	internalServerError bool
+}
+
+// WithOptions sets the options used to create the Goalie of each request.
+func WithOptions(options ...goalie.Option) Option {
+	return func(c *config) {
+		c.options = append(c.options, options...)
+	}
+}
+
+// WithReporter sets the function used to report cleanup errors.
+// By default, the errors are printed with [log.Printf].
+func WithReporter(reporter Reporter) Option {
+	return func(c *config) {
+		c.reporter = reporter
+	}
+}
+
+// WithInternalServerError makes [Middleware] respond with 500 Internal Server Error
+// when a cleanup fails and the handler has not written the response yet.
+func WithInternalServerError() Option {
+	return func(c *config) {
+		c.internalServerError = true
+	}
+}
+
+func defaultReporter(r *http.Request, err error) {
+	log.Printf("httpgoalie: %s %s: cleanup failed: %+v", r.Method, r.URL.Path, err)
+}
+
+// Middleware returns a middleware that creates a Goalie for each request.
+//
+// The Goalie is stored in the request context, so handlers and lower layers can get it with [goalie.FromContext].
+// After the handler returns, the middleware collects the captured errors and reports them to the [Reporter].
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+//		g, _ := goalie.FromContext(r.Context())
+//		f, err := os.Open("index.html")
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusInternalServerError)
+//			return
+//		}
+//		g.Defer(f.Close)
+//
+//		_, _ = io.Copy(w, f)
+//	})
+//	http.ListenAndServe(":8080", httpgoalie.Middleware()(mux))
+func Middleware(options ...Option) func(http.Handler) http.Handler {
+	c := config{
+		reporter: defaultReporter,
+	}
+	for _, o := range options {
+		o(&c)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w}
+			err := goalie.Run(func(g *goalie.Goalie) error {
+				next.ServeHTTP(rw, r.WithContext(goalie.NewContext(r.Context(), g)))
+				return nil
+			}, c.options...)
+			if err == nil {
+				return
+			}
+
+			c.reporter(r, err)
+			if c.internalServerError && !rw.written {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+// responseWriter records whether the response has been written,
+// forwarding the optional interfaces of the original [http.ResponseWriter].
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements [http.Flusher] for streaming handlers.
+func (w *responseWriter) Flush() {
+	w.written = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements [http.Hijacker], returning [http.ErrNotSupported] if the original writer does not support it.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.written = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// ReadFrom implements [io.ReaderFrom] so that the original writer can copy efficiently.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.written = true
+	return io.Copy(w.ResponseWriter, r)
+}
+
+// Unwrap returns the original [http.ResponseWriter] for [http.ResponseController].
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,170 @@
+package httpgoalie_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ras0q/goalie"
+	"github.com/ras0q/goalie/httpgoalie"
+)
+
+func assert[T comparable](t *testing.T, expected, got T) {
+	t.Helper()
+	if got != expected {
+		t.Fatalf("assertion failed (expected: %+v, got: %+v)", expected, got)
+	}
+}
+
+func Test_Middleware(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+
+	type testcase struct {
+		cleanupErr          error
+		writeResponse       bool
+		internalServerError bool
+		expectedStatus      int
+		isReported          bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var reported error
+		options := []httpgoalie.Option{
+			httpgoalie.WithReporter(func(r *http.Request, err error) {
+				reported = err
+			}),
+		}
+		if tc.internalServerError {
+			options = append(options, httpgoalie.WithInternalServerError())
+		}
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			g, ok := goalie.FromContext(r.Context())
+			assert(t, true, ok)
+			g.Defer(func() error { return tc.cleanupErr })
+
+			if tc.writeResponse {
+				w.WriteHeader(http.StatusAccepted)
+			}
+		})
+
+		rec := httptest.NewRecorder()
+		httpgoalie.Middleware(options...)(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert(t, tc.expectedStatus, rec.Code)
+		assert(t, tc.isReported, errors.Is(reported, errCleanup))
+	}
+
+	testcases := map[string]testcase{
+		"no cleanup error": {
+			writeResponse:  true,
+			expectedStatus: http.StatusAccepted,
+		},
+		"report cleanup error": {
+			cleanupErr:     errCleanup,
+			writeResponse:  true,
+			expectedStatus: http.StatusAccepted,
+			isReported:     true,
+		},
+		"internal server error before response": {
+			cleanupErr:          errCleanup,
+			internalServerError: true,
+			expectedStatus:      http.StatusInternalServerError,
+			isReported:          true,
+		},
+		"keep written response": {
+			cleanupErr:          errCleanup,
+			writeResponse:       true,
+			internalServerError: true,
+			expectedStatus:      http.StatusAccepted,
+			isReported:          true,
+		},
+		"no internal server error without option": {
+			cleanupErr:     errCleanup,
+			expectedStatus: http.StatusOK,
+			isReported:     true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Middleware_Streaming(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+
+	type testcase struct {
+		stream    func(w http.ResponseWriter) error
+		isFlushed bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var reported error
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			g, ok := goalie.FromContext(r.Context())
+			assert(t, true, ok)
+			g.Defer(func() error { return errCleanup })
+
+			_, ok = w.(http.Hijacker)
+			assert(t, true, ok)
+			assert(t, nil, tc.stream(w))
+		})
+
+		rec := httptest.NewRecorder()
+		httpgoalie.Middleware(
+			httpgoalie.WithReporter(func(r *http.Request, err error) {
+				reported = err
+			}),
+			httpgoalie.WithInternalServerError(),
+		)(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// The response has already been sent, so it must not be replaced with an internal server error.
+		assert(t, http.StatusOK, rec.Code)
+		assert(t, tc.isFlushed, rec.Flushed)
+		assert(t, "data: ok\n\n", rec.Body.String())
+		assert(t, true, errors.Is(reported, errCleanup))
+	}
+
+	testcases := map[string]testcase{
+		"flush with type assertion": {
+			stream: func(w http.ResponseWriter) error {
+				_, _ = io.WriteString(w, "data: ok\n\n")
+				w.(http.Flusher).Flush()
+				return nil
+			},
+			isFlushed: true,
+		},
+		"flush with response controller": {
+			stream: func(w http.ResponseWriter) error {
+				rc := http.NewResponseController(w)
+				if err := rc.Flush(); err != nil {
+					return err
+				}
+				_, err := io.WriteString(w, "data: ok\n\n")
+				return err
+			},
+			isFlushed: true,
+		},
+		"copy with reader from": {
+			stream: func(w http.ResponseWriter) error {
+				_, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("data: ok\n\n"))
+				return err
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package goalie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorJSON is the JSON representation of an [*Error], as produced by [Error.MarshalJSON].
+//
+// Example:
+//
+//	{
+//	  "primary": "internal error",
+//	  "cleanups": [
+//	    {
+//	      "label": "os.(*File).Close",
+//	      "message": "close go.mod: file already closed",
+//	      "site": "/path/to/main.go:42",
+//	      "type": "*fs.PathError",
+//	      "duration_ns": 12000
+//	    }
+//	  ]
+//	}
+type ErrorJSON struct {
+	// Primary is the message of the primary error, or empty if there is none.
+	Primary string `json:"primary,omitempty"`
+	// Cleanups are the cleanup errors in capture order.
+	Cleanups []CleanupErrorJSON `json:"cleanups"`
+}
+
+// CleanupErrorJSON is the JSON representation of a [*CleanupError].
+type CleanupErrorJSON struct {
+	Label    string        `json:"label"`
+	Message  string        `json:"message"`
+	Site     string        `json:"site,omitempty"`
+	Type     string        `json:"type"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// MarshalJSON implements [json.Marshaler] using the schema of [ErrorJSON].
+func (e *Error) MarshalJSON() ([]byte, error) {
+	ej := ErrorJSON{
+		Cleanups: make([]CleanupErrorJSON, 0, len(e.Cleanups)),
+	}
+	if e.Primary != nil {
+		ej.Primary = e.Primary.Error()
+	}
+	for _, c := range e.Cleanups {
+		ej.Cleanups = append(ej.Cleanups, CleanupErrorJSON{
+			Label:    c.Label,
+			Message:  c.Err.Error(),
+			Site:     c.Site,
+			Type:     fmt.Sprintf("%T", c.Err),
+			Duration: c.Duration,
+		})
+	}
+
+	return json.Marshal(ej)
+}
+
+// ParseErrorJSON parses data produced by [Error.MarshalJSON].
+//
+// This is useful for tooling that reads collected errors back from logs.
+func ParseErrorJSON(data []byte) (*ErrorJSON, error) {
+	var ej ErrorJSON
+	if err := json.Unmarshal(data, &ej); err != nil {
+		return nil, fmt.Errorf("failed to parse goalie error: %w", err)
+	}
+	if ej.Cleanups == nil && ej.Primary == "" {
+		return nil, errors.New("failed to parse goalie error: no primary or cleanups field")
+	}
+
+	return &ej, nil
+}
+
+// ToError rebuilds an [*Error] from ej so that it can be formatted again.
+// The original error types are lost; every message becomes a plain error.
+func (ej *ErrorJSON) ToError() *Error {
+	e := &Error{
+		Cleanups: make([]*CleanupError, 0, len(ej.Cleanups)),
+	}
+	if ej.Primary != "" {
+		e.Primary = errors.New(ej.Primary)
+	}
+	for _, c := range ej.Cleanups {
+		e.Cleanups = append(e.Cleanups, &CleanupError{
+			Label:    c.Label,
+			Site:     c.Site,
+			Duration: c.Duration,
+			Err:      errors.New(c.Message),
+		})
+	}
+
+	return e
+}
@@ -0,0 +1,100 @@
+package goalie_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Error_MarshalJSON(t *testing.T) {
+	type testcase struct {
+		err      error
+		expected string
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		data, err := json.Marshal(tc.err)
+		assert(t, nil, err)
+		assert(t, tc.expected, string(data))
+	}
+
+	testcases := map[string]testcase{
+		"primary and cleanup": {
+			err: goalie.Join(errInternal, &goalie.CleanupError{
+				Label:    "os.(*File).Close",
+				Site:     "main.go:42",
+				Duration: 12 * time.Microsecond,
+				Err:      &os.PathError{Op: "close", Path: "go.mod", Err: os.ErrClosed},
+			}),
+			expected: `{"primary":"internal error","cleanups":[{"label":"os.(*File).Close","message":"close go.mod: file already closed","site":"main.go:42","type":"*fs.PathError","duration_ns":12000}]}`,
+		},
+		"no primary": {
+			err: goalie.Join(&goalie.CleanupError{
+				Label: "close",
+				Err:   errors.New("failed"),
+			}),
+			expected: `{"cleanups":[{"label":"close","message":"failed","type":"*errors.errorString","duration_ns":0}]}`,
+		},
+		"no cleanups": {
+			err:      goalie.Join(errInternal),
+			expected: `{"primary":"internal error","cleanups":[]}`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_ParseErrorJSON(t *testing.T) {
+	type testcase struct {
+		data          string
+		isParseError  bool
+		expectedError string
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		ej, err := goalie.ParseErrorJSON([]byte(tc.data))
+		assert(t, tc.isParseError, err != nil)
+		if err != nil {
+			return
+		}
+
+		assert(t, tc.expectedError, fmt.Sprintf("%+v", ej.ToError()))
+	}
+
+	testcases := map[string]testcase{
+		"round trip": {
+			data: `{"primary":"internal error","cleanups":[{"label":"close","message":"failed","site":"main.go:42","type":"*errors.errorString","duration_ns":12000}]}`,
+			expectedError: "internal error\n" +
+				"cleanup errors (1):\n" +
+				"  [1] close at main.go:42 (took 12µs)\n" +
+				"      failed",
+		},
+		"invalid json": {
+			data:         `{`,
+			isParseError: true,
+		},
+		"not a goalie error": {
+			data:         `{"message":"failed"}`,
+			isParseError: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
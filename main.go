@@ -0,0 +1,134 @@
+package goalie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Exit codes used by [DefaultExitCode].
+const (
+	// ExitCodeOK is used when no error is collected.
+	ExitCodeOK = 0
+	// ExitCodeFailure is used when the body of [Main] fails.
+	ExitCodeFailure = 1
+	// ExitCodeCleanupFailure is used when the body of [Main] succeeds but a cleanup fails.
+	ExitCodeCleanupFailure = 3
+)
+
+// ExitCodeFunc maps the error collected by [Main] to a process exit code.
+type ExitCodeFunc func(err error) int
+
+// DefaultExitCode is the default [ExitCodeFunc].
+// It returns [ExitCodeOK] for nil, [ExitCodeCleanupFailure] if only cleanups failed,
+// and [ExitCodeFailure] otherwise.
+func DefaultExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var gerr *Error
+	if errors.As(err, &gerr) && gerr.Primary == nil {
+		return ExitCodeCleanupFailure
+	}
+
+	return ExitCodeFailure
+}
+
+// MainOption is a function that configures [Main].
+type MainOption func(*mainConfig)
+
+type mainConfig struct {
+	options      []Option
+	signals      []os.Signal
+	stderr       io.Writer
+	exitCodeFunc ExitCodeFunc
+}
+
+// WithOptions sets the options used to create the Goalie passed to the body of [Main].
+func WithOptions(options ...Option) MainOption {
+	return func(c *mainConfig) {
+		c.options = append(c.options, options...)
+	}
+}
+
+// WithSignals sets the signals that cancel the context passed to the body of [Main].
+// By default, [os.Interrupt] and [syscall.SIGTERM] are used.
+func WithSignals(signals ...os.Signal) MainOption {
+	return func(c *mainConfig) {
+		c.signals = signals
+	}
+}
+
+// WithStderr sets the writer to which [Main] prints the collected error.
+// By default, [os.Stderr] is used.
+func WithStderr(stderr io.Writer) MainOption {
+	return func(c *mainConfig) {
+		c.stderr = stderr
+	}
+}
+
+// WithExitCodeFunc sets the function used to map the collected error to an exit code.
+// By default, [DefaultExitCode] is used.
+func WithExitCodeFunc(exitCodeFunc ExitCodeFunc) MainOption {
+	return func(c *mainConfig) {
+		c.exitCodeFunc = exitCodeFunc
+	}
+}
+
+// Main runs body as the entry point of a program and exits the process.
+//
+// The context passed to body is cancelled when the process receives SIGINT or SIGTERM,
+// so body can return early and let all cleanups run.
+// A second signal terminates the process immediately.
+// The collected error is printed to stderr with %+v, and the process exits
+// with the code returned by the [ExitCodeFunc].
+//
+// Example:
+//
+//	func main() {
+//		goalie.Main(func(ctx context.Context, g *goalie.Goalie) error {
+//			f, err := os.Open("go.mod")
+//			if err != nil {
+//				return err
+//			}
+//			defer g.Guard(f.Close)
+//
+//			// ... do something ...
+//			return nil
+//		})
+//	}
+func Main(body func(ctx context.Context, g *Goalie) error, options ...MainOption) {
+	os.Exit(RunMain(body, options...))
+}
+
+// RunMain is like [Main] but returns the exit code instead of exiting the process.
+func RunMain(body func(ctx context.Context, g *Goalie) error, options ...MainOption) int {
+	c := mainConfig{
+		signals:      []os.Signal{os.Interrupt, syscall.SIGTERM},
+		stderr:       os.Stderr,
+		exitCodeFunc: DefaultExitCode,
+	}
+	for _, o := range options {
+		o(&c)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), c.signals...)
+	defer stop()
+	// Stop catching signals once the first one arrives,
+	// so that a second signal terminates the process even if a cleanup hangs.
+	context.AfterFunc(ctx, stop)
+
+	err := Run(func(g *Goalie) error {
+		return body(ctx, g)
+	}, c.options...)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "%+v\n", err)
+	}
+
+	return c.exitCodeFunc(err)
+}
@@ -0,0 +1,158 @@
+package goalie_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_RunMain(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+
+	type testcase struct {
+		bodyErr      error
+		cleanupErr   error
+		exitCodeFunc goalie.ExitCodeFunc
+		expectedCode int
+		isReported   bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var stderr bytes.Buffer
+		options := []goalie.MainOption{goalie.WithStderr(&stderr)}
+		if tc.exitCodeFunc != nil {
+			options = append(options, goalie.WithExitCodeFunc(tc.exitCodeFunc))
+		}
+
+		code := goalie.RunMain(func(ctx context.Context, g *goalie.Goalie) error {
+			defer g.Guard(func() error { return tc.cleanupErr })
+			return tc.bodyErr
+		}, options...)
+		assert(t, tc.expectedCode, code)
+		assert(t, tc.isReported, stderr.Len() > 0)
+	}
+
+	testcases := map[string]testcase{
+		"success": {
+			expectedCode: goalie.ExitCodeOK,
+			isReported:   false,
+		},
+		"body failure": {
+			bodyErr:      errInternal,
+			expectedCode: goalie.ExitCodeFailure,
+			isReported:   true,
+		},
+		"body and cleanup failure": {
+			bodyErr:      errInternal,
+			cleanupErr:   errCleanup,
+			expectedCode: goalie.ExitCodeFailure,
+			isReported:   true,
+		},
+		"cleanup failure only": {
+			cleanupErr:   errCleanup,
+			expectedCode: goalie.ExitCodeCleanupFailure,
+			isReported:   true,
+		},
+		"custom exit code": {
+			cleanupErr:   errCleanup,
+			exitCodeFunc: func(err error) int { return 42 },
+			expectedCode: 42,
+			isReported:   true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_RunMain_Signal(t *testing.T) {
+	var stderr bytes.Buffer
+	cleaned := false
+	code := goalie.RunMain(func(ctx context.Context, g *goalie.Goalie) error {
+		defer g.Guard(func() error {
+			cleaned = true
+			return errors.New("cleanup after signal")
+		})
+
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		if err := p.Signal(syscall.SIGTERM); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("context was not cancelled by signal")
+		}
+	}, goalie.WithStderr(&stderr))
+
+	assert(t, goalie.ExitCodeFailure, code)
+	assert(t, true, cleaned)
+	assert(t, true, strings.Contains(stderr.String(), "cleanup after signal"))
+	assert(t, true, strings.Contains(stderr.String(), "context canceled"))
+}
+
+func Test_RunMain_SecondSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM is not supported on Windows")
+	}
+
+	const helperEnv = "GOALIE_MAIN_HELPER"
+	if os.Getenv(helperEnv) != "" {
+		sigterm := func() error {
+			p, err := os.FindProcess(os.Getpid())
+			if err != nil {
+				return err
+			}
+
+			return p.Signal(syscall.SIGTERM)
+		}
+
+		os.Exit(goalie.RunMain(func(ctx context.Context, g *goalie.Goalie) error {
+			// This cleanup hangs until a second signal terminates the process.
+			defer g.Guard(func() error {
+				for range 100 {
+					if err := sigterm(); err != nil {
+						return err
+					}
+					time.Sleep(100 * time.Millisecond)
+				}
+
+				return nil
+			})
+
+			if err := sigterm(); err != nil {
+				return err
+			}
+			<-ctx.Done()
+
+			return nil
+		}))
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_RunMain_SecondSignal$")
+	cmd.Env = append(os.Environ(), helperEnv+"=1")
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	assert(t, true, errors.As(err, &exitErr))
+	assert(t, false, exitErr.Exited())
+}
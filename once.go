@@ -0,0 +1,32 @@
+package goalie
+
+import "sync"
+
+// Once returns a function that calls errFunc only the first time it is called
+// and returns that first result to every caller. It is safe for concurrent use.
+//
+// This allows a resource to be closed explicitly to check the error early,
+// while still being guarded in case of an early return, without a double-close error.
+// Since the returned function hides the name of errFunc, use [Goalie.GuardLabel] to keep a meaningful label.
+// Note that every caller receives the same error, so if the result of an explicit call is returned
+// while the function is also guarded, a failure is reported twice: as the primary error and as a cleanup error.
+//
+// Example:
+//
+//	f, err := os.Create(path)
+//	if err != nil {
+//		return err
+//	}
+//	closeFile := goalie.Once(f.Close)
+//	defer g.GuardLabel("close "+path, closeFile)
+//
+//	if _, err := f.Write(data); err != nil {
+//		return err
+//	}
+//	// Close the file early to release it; a failure is reported by the deferred guard.
+//	_ = closeFile()
+//
+//	return upload(path)
+func Once(errFunc func() error) func() error {
+	return sync.OnceValue(errFunc)
+}
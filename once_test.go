@@ -0,0 +1,102 @@
+package goalie_test
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Once(t *testing.T) {
+	type testcase struct {
+		err   error
+		calls int
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var count atomic.Int32
+		once := goalie.Once(func() error {
+			count.Add(1)
+			return tc.err
+		})
+
+		errs := make([]error, tc.calls)
+		var wg sync.WaitGroup
+		for i := range tc.calls {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = once()
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert(t, tc.err, err)
+		}
+
+		assert(t, int32(1), count.Load())
+	}
+
+	testcases := map[string]testcase{
+		"call once": {
+			calls: 1,
+		},
+		"return first error to every caller": {
+			err:   errInternal,
+			calls: 10,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Once_CloseTwice(t *testing.T) {
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		f, err := os.Open("once_test.go")
+		if err != nil {
+			return err
+		}
+		closeFile := goalie.Once(f.Close)
+		defer g.GuardLabel("close once_test.go", closeFile)
+
+		return closeFile()
+	}()
+	assert(t, nil, err)
+}
+
+func Test_Once_CloseFailure(t *testing.T) {
+	calls := 0
+	err := func() (err error) {
+		g := goalie.New()
+		defer g.Collect(&err)
+
+		closeFunc := goalie.Once(func() error {
+			calls++
+			return errInternal
+		})
+		defer g.GuardLabel("close", closeFunc)
+
+		return closeFunc()
+	}()
+	assert(t, 1, calls)
+
+	// The failure is reported both as the primary error and as a cleanup error.
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, errInternal, gerr.Primary)
+	assert(t, 1, len(gerr.Cleanups))
+	assert(t, errInternal, gerr.Cleanups[0].Err)
+}
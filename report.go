@@ -0,0 +1,95 @@
+package goalie
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Record is an entry of the audit trail kept by a Goalie created with [WithRecording].
+type Record struct {
+	// Label describes the cleanup, e.g. "os.(*File).Close".
+	Label string
+	// Site is the "file:line" location in the caller's code where the cleanup was guarded,
+	// e.g. by [Goalie.Guard], or where it was registered to run in [Goalie.Collect], e.g. by [Goalie.Defer].
+	Site string
+	// Duration is how long the cleanup took.
+	Duration time.Duration
+	// Err is the error recorded for the cleanup, or nil if it succeeded.
+	Err error
+}
+
+// Report is the audit trail of every cleanup run by a Goalie, in execution order.
+//
+// It prints one line per cleanup:
+//
+//	ok   os.(*File).Close at /path/to/main.go:42 (took 12µs)
+//	FAIL (*sql.Tx).Rollback at /path/to/main.go:50 (took 3ms): sql: connection is already closed
+type Report []Record
+
+func (r Report) String() string {
+	var b strings.Builder
+	for i, rec := range r {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		outcome := "ok  "
+		if rec.Err != nil {
+			outcome = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s %s", outcome, rec.Label)
+		if rec.Site != "" {
+			fmt.Fprintf(&b, " at %s", rec.Site)
+		}
+		fmt.Fprintf(&b, " (took %s)", rec.Duration)
+		if rec.Err != nil {
+			fmt.Fprintf(&b, ": %s", strings.ReplaceAll(rec.Err.Error(), "\n", "; "))
+		}
+	}
+
+	return b.String()
+}
+
+// WithRecording makes a Goalie keep an audit trail of every cleanup it runs,
+// including successful ones, which can be retrieved with [Goalie.Report].
+//
+// Recording does not change the error assigned by [Goalie.Collect].
+func WithRecording() Option {
+	return func(g *Goalie) {
+		g.recording = true
+	}
+}
+
+// Report returns the audit trail of every cleanup run so far.
+// It returns nil unless the Goalie is created with [WithRecording].
+//
+// Example:
+//
+//	g := goalie.New(goalie.WithRecording())
+//	defer func() {
+//		log.Printf("cleanups:\n%s", g.Report())
+//	}()
+//	defer g.Collect(&err)
+func (g *Goalie) Report() Report {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.records == nil {
+		return nil
+	}
+
+	return append(Report(nil), g.records...)
+}
+
+// record appends rec to the audit trail if recording is enabled.
+func (g *Goalie) record(rec Record) {
+	if !g.recording {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.records = append(g.records, rec)
+}
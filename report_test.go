@@ -0,0 +1,66 @@
+package goalie_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Report(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+
+	type testcase struct {
+		options         []goalie.Option
+		expectedReport  string
+		isCleanupError  bool
+		expectedRecords int
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		g := goalie.New(append(tc.options, goalie.WithClock(clock.Now))...)
+		err := func() (err error) {
+			defer g.Collect(&err)
+
+			g.Defer((&closer{err: errCleanup}).Close)
+			defer goalie.Guard1(g, clock.Sleep, 2*time.Millisecond)
+
+			return nil
+		}()
+		assert(t, tc.isCleanupError, errors.Is(err, errCleanup))
+
+		report := g.Report()
+		assert(t, tc.expectedRecords, len(report))
+		for i := range report {
+			// Site depends on the environment.
+			report[i].Site = ""
+		}
+		assert(t, tc.expectedReport, report.String())
+	}
+
+	testcases := map[string]testcase{
+		"without recording": {
+			options:         nil,
+			expectedReport:  "",
+			isCleanupError:  true,
+			expectedRecords: 0,
+		},
+		"with recording": {
+			options: []goalie.Option{goalie.WithRecording()},
+			expectedReport: "ok   github.com/ras0q/goalie_test.(*fakeClock).Sleep (took 2ms)\n" +
+				"FAIL github.com/ras0q/goalie_test.(*closer).Close (took 0s): cleanup",
+			isCleanupError:  true,
+			expectedRecords: 2,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package goalie
+
+// Run creates a new Goalie, calls body with it and collects all captured errors.
+//
+// Unlike [Goalie.Collect], Run does not require the caller to have a named error result.
+//
+// Example:
+//
+//	func processFile(path string) error {
+//		return goalie.Run(func(g *goalie.Goalie) error {
+//			f, err := os.Open(path)
+//			if err != nil {
+//				return err
+//			}
+//			defer g.Guard(f.Close)
+//
+//			// ... do something ...
+//			return nil
+//		})
+//	}
+func Run(body func(g *Goalie) error, options ...Option) (err error) {
+	g := New(options...)
+	defer g.Collect(&err)
+
+	return body(g)
+}
+
+// Run1 is like [Run] but for a body returning one value in addition to an error.
+//
+// Example:
+//
+//	func countLines(path string) (int, error) {
+//		return goalie.Run1(func(g *goalie.Goalie) (int, error) {
+//			f, err := os.Open(path)
+//			if err != nil {
+//				return 0, err
+//			}
+//			defer g.Guard(f.Close)
+//
+//			// ... count lines ...
+//			return n, nil
+//		})
+//	}
+func Run1[T any](body func(g *Goalie) (T, error), options ...Option) (_ T, err error) {
+	g := New(options...)
+	defer g.Collect(&err)
+
+	return body(g)
+}
+
+// Run2 is like [Run] but for a body returning two values in addition to an error.
+func Run2[T, U any](body func(g *Goalie) (T, U, error), options ...Option) (_ T, _ U, err error) {
+	g := New(options...)
+	defer g.Collect(&err)
+
+	return body(g)
+}
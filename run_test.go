@@ -0,0 +1,114 @@
+package goalie_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_Run(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+	errWrapped := errors.New("wrapped")
+
+	type testcase struct {
+		bodyErr          error
+		cleanupErr       error
+		isInternalError  bool
+		isCleanupError   bool
+		isWrapped        bool
+		useWrapErrorFunc bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var options []goalie.Option
+		if tc.useWrapErrorFunc {
+			options = append(options, goalie.WithWrapErrorFunc(func(err error) error {
+				return errors.Join(errWrapped, err)
+			}))
+		}
+
+		body := func(g *goalie.Goalie) error {
+			defer g.Guard(func() error { return tc.cleanupErr })
+			return tc.bodyErr
+		}
+
+		err := goalie.Run(body, options...)
+		assert(t, tc.isInternalError, errors.Is(err, errInternal))
+		assert(t, tc.isCleanupError, errors.Is(err, errCleanup))
+		assert(t, tc.isWrapped, errors.Is(err, errWrapped))
+
+		n, err := goalie.Run1(func(g *goalie.Goalie) (int, error) {
+			return 1, body(g)
+		}, options...)
+		assert(t, 1, n)
+		assert(t, tc.isInternalError, errors.Is(err, errInternal))
+		assert(t, tc.isCleanupError, errors.Is(err, errCleanup))
+		assert(t, tc.isWrapped, errors.Is(err, errWrapped))
+
+		n, s, err := goalie.Run2(func(g *goalie.Goalie) (int, string, error) {
+			return 2, "two", body(g)
+		}, options...)
+		assert(t, 2, n)
+		assert(t, "two", s)
+		assert(t, tc.isInternalError, errors.Is(err, errInternal))
+		assert(t, tc.isCleanupError, errors.Is(err, errCleanup))
+		assert(t, tc.isWrapped, errors.Is(err, errWrapped))
+	}
+
+	testcases := map[string]testcase{
+		"no errors": {},
+		"body error only": {
+			bodyErr:         errInternal,
+			isInternalError: true,
+		},
+		"cleanup error only": {
+			cleanupErr:     errCleanup,
+			isCleanupError: true,
+		},
+		"both errors": {
+			bodyErr:         errInternal,
+			cleanupErr:      errCleanup,
+			isInternalError: true,
+			isCleanupError:  true,
+		},
+		"options are applied": {
+			cleanupErr:       errCleanup,
+			isCleanupError:   true,
+			isWrapped:        true,
+			useWrapErrorFunc: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Run_UnnamedResults(t *testing.T) {
+	// countLinesWithRun is countLines rewritten with unnamed results.
+	countLinesWithRun := func(path string) (int, error) {
+		return goalie.Run1(func(g *goalie.Goalie) (int, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return -1, err
+			}
+			defer g.Guard(f.Close)
+
+			if err := f.Close(); err != nil {
+				return -1, err
+			}
+
+			return -1, errInternal
+		})
+	}
+
+	_, err := countLinesWithRun("run_test.go")
+	assert(t, true, errors.Is(err, os.ErrClosed))
+	assert(t, true, errors.Is(err, errInternal))
+}
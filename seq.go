@@ -0,0 +1,75 @@
+package goalie
+
+import "iter"
+
+// GuardSeq wraps seq, which streams from a resource released by closeFunc,
+// so that closeFunc is guarded when the iteration stops for any reason:
+// full consumption, an early break or a panic.
+//
+// The returned iterator is single-use, and the resource is not released if it is never ranged over.
+//
+// Example:
+//
+//	f, err := os.Open(path)
+//	if err != nil {
+//		return err
+//	}
+//	for line := range goalie.GuardSeq(g, lines(f), f.Close) {
+//		if line == "" {
+//			break
+//		}
+//	}
+func GuardSeq[V any](g *Goalie, seq iter.Seq[V], closeFunc func() error) iter.Seq[V] {
+	o := origin{labelFunc: closeFunc, site: callerSite()}
+
+	return func(yield func(V) bool) {
+		defer g.guard(o, closeFunc)
+		seq(yield)
+	}
+}
+
+// GuardSeq2 is like [GuardSeq] but for [iter.Seq2].
+func GuardSeq2[K, V any](g *Goalie, seq iter.Seq2[K, V], closeFunc func() error) iter.Seq2[K, V] {
+	o := origin{labelFunc: closeFunc, site: callerSite()}
+
+	return func(yield func(K, V) bool) {
+		defer g.guard(o, closeFunc)
+		seq(yield)
+	}
+}
+
+// GuardSeqErr is like [GuardSeq] but joins the error of closeFunc into errp
+// in the same way as [Goalie.Collect] when the iteration stops.
+//
+// Example:
+//
+//	var err error
+//	for row := range goalie.GuardSeqErr(&err, rows(db), db.Close) {
+//		fmt.Println(row)
+//	}
+//	return err
+func GuardSeqErr[V any](errp *error, seq iter.Seq[V], closeFunc func() error) iter.Seq[V] {
+	o := origin{labelFunc: closeFunc, site: callerSite()}
+
+	return func(yield func(V) bool) {
+		defer collectTo(errp, o, closeFunc)
+		seq(yield)
+	}
+}
+
+// GuardSeq2Err is like [GuardSeqErr] but for [iter.Seq2].
+func GuardSeq2Err[K, V any](errp *error, seq iter.Seq2[K, V], closeFunc func() error) iter.Seq2[K, V] {
+	o := origin{labelFunc: closeFunc, site: callerSite()}
+
+	return func(yield func(K, V) bool) {
+		defer collectTo(errp, o, closeFunc)
+		seq(yield)
+	}
+}
+
+// collectTo guards closeFunc with a new Goalie and collects its error into errp.
+func collectTo(errp *error, o origin, closeFunc func() error) {
+	g := New()
+	g.guard(o, closeFunc)
+	g.Collect(errp)
+}
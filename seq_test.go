@@ -0,0 +1,162 @@
+package goalie_test
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+// stopMode is how the consumer stops ranging over an iterator.
+type stopMode int
+
+const (
+	stopAtEnd stopMode = iota
+	stopByBreak
+	stopByPanic
+)
+
+// consume ranges over seq until it stops as mode, reporting how many values were received
+// and whether a panic was recovered.
+func consume(seq iter.Seq[int], mode stopMode) (n int, panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+
+	for range seq {
+		n++
+		switch mode {
+		case stopByBreak:
+			return n, false
+		case stopByPanic:
+			panic("consumer panic")
+		}
+	}
+
+	return n, false
+}
+
+func Test_GuardSeq(t *testing.T) {
+	type testcase struct {
+		mode       stopMode
+		closeErr   error
+		received   int
+		isPanicked bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		t.Run("Goalie", func(t *testing.T) {
+			c := &closer{err: tc.closeErr}
+
+			var received int
+			var panicked bool
+			err := func() (err error) {
+				g := goalie.New()
+				defer g.Collect(&err)
+
+				received, panicked = consume(goalie.GuardSeq(g, slices.Values([]int{1, 2, 3}), c.Close), tc.mode)
+
+				return nil
+			}()
+			assert(t, true, c.closed)
+			assert(t, tc.received, received)
+			assert(t, tc.isPanicked, panicked)
+			assert(t, tc.closeErr != nil, errors.Is(err, errInternal))
+
+			if err != nil {
+				var gerr *goalie.Error
+				assert(t, true, errors.As(err, &gerr))
+				assert(t, "github.com/ras0q/goalie_test.(*closer).Close", gerr.Cleanups[0].Label)
+			}
+		})
+
+		t.Run("error pointer", func(t *testing.T) {
+			c := &closer{err: tc.closeErr}
+
+			var err error
+			received, panicked := consume(goalie.GuardSeqErr(&err, slices.Values([]int{1, 2, 3}), c.Close), tc.mode)
+			assert(t, true, c.closed)
+			assert(t, tc.received, received)
+			assert(t, tc.isPanicked, panicked)
+			assert(t, tc.closeErr != nil, errors.Is(err, errInternal))
+		})
+	}
+
+	testcases := map[string]testcase{
+		"close after full consumption": {
+			mode:     stopAtEnd,
+			received: 3,
+		},
+		"close after early break": {
+			mode:     stopByBreak,
+			received: 1,
+		},
+		"close after panic": {
+			mode:       stopByPanic,
+			received:   1,
+			isPanicked: true,
+		},
+		"capture close error after full consumption": {
+			mode:     stopAtEnd,
+			closeErr: errInternal,
+			received: 3,
+		},
+		"capture close error after early break": {
+			mode:     stopByBreak,
+			closeErr: errInternal,
+			received: 1,
+		},
+		"capture close error after panic": {
+			mode:       stopByPanic,
+			closeErr:   errInternal,
+			received:   1,
+			isPanicked: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_GuardSeq2(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+
+	t.Run("Goalie", func(t *testing.T) {
+		c := &closer{err: errInternal}
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+
+			for range goalie.GuardSeq2(g, maps.All(m), c.Close) {
+				break
+			}
+			assert(t, true, c.closed)
+
+			return nil
+		}()
+		assert(t, true, errors.Is(err, errInternal))
+	})
+
+	t.Run("error pointer", func(t *testing.T) {
+		c := &closer{err: errInternal}
+		err := errors.New("primary")
+		primary := err
+
+		n := 0
+		for range goalie.GuardSeq2Err(&err, maps.All(m), c.Close) {
+			n++
+		}
+		assert(t, len(m), n)
+		assert(t, true, c.closed)
+		assert(t, true, errors.Is(err, primary))
+		assert(t, true, errors.Is(err, errInternal))
+	})
+}
@@ -0,0 +1,263 @@
+// Package shutdown coordinates a phased graceful shutdown and collects
+// every cleanup failure into a single [goalie.Error].
+//
+// Cleanups are registered into named phases, such as "listeners", "workers" and "databases".
+// Phases run sequentially in an order satisfying their ordering constraints,
+// each within its own time budget, while cleanups inside a phase run concurrently.
+// Each phase is collected with a [goalie.Goalie], so cleanup errors are captured in the same way.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+// ErrTimeout is reported for a cleanup that did not finish within the time budget of its phase.
+var ErrTimeout = errors.New("cleanup did not finish within the phase budget")
+
+// Coordinator manages shutdown phases and their cleanups.
+// It is safe for concurrent use.
+type Coordinator struct {
+	mu      sync.Mutex
+	options []goalie.Option
+	phases  []*phase
+}
+
+type phase struct {
+	name   string
+	budget time.Duration
+	after  []string
+	g      *goalie.Goalie
+	// ctx is passed to the cleanups. It is set by Coordinator.Shutdown before collecting g.
+	ctx context.Context
+	// running tracks the cleanups, including those still running after the budget is exceeded.
+	running sync.WaitGroup
+}
+
+// New creates a new Coordinator.
+//
+// The options configure the Goalie collecting each phase, e.g. [goalie.WithWrapErrorFunc],
+// [goalie.WithTypedNilPolicy] and [goalie.WithMaxConcurrency].
+// The errors are always joined into a [goalie.Error], so [goalie.WithJoinErrorsFunc] has no effect.
+func New(options ...goalie.Option) *Coordinator {
+	return &Coordinator{
+		options: append(slices.Clone(options), goalie.WithJoinErrorsFunc(goalie.Join)),
+	}
+}
+
+// PhaseOption is a function that configures a phase.
+type PhaseOption func(*phase)
+
+// Budget sets the time budget of a phase.
+// Cleanups still running when the budget is exceeded are reported as [ErrTimeout].
+// Since the ordering constraints would be broken otherwise, the next phase does not start
+// until they return, unless the context passed to [Coordinator.Shutdown] is done.
+// By default, a phase has no budget other than the deadline of the context passed to [Coordinator.Shutdown].
+func Budget(budget time.Duration) PhaseOption {
+	return func(p *phase) {
+		p.budget = budget
+	}
+}
+
+// After declares that a phase runs after the given phases.
+func After(names ...string) PhaseOption {
+	return func(p *phase) {
+		p.after = append(p.after, names...)
+	}
+}
+
+// Phase declares the phase name, or reconfigures it if already declared.
+//
+// Phases without ordering constraints between them run in declaration order.
+//
+// Example:
+//
+//	c := shutdown.New()
+//	c.Phase("listeners", shutdown.Budget(5*time.Second))
+//	c.Phase("workers", shutdown.After("listeners"), shutdown.Budget(30*time.Second))
+//	c.Phase("databases", shutdown.After("workers"))
+func (c *Coordinator) Phase(name string, options ...PhaseOption) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := c.phase(name)
+	for _, o := range options {
+		o(p)
+	}
+}
+
+// Register registers fn to run in the phase name with the given label.
+// The phase is declared with no options if it is not declared yet.
+//
+// Example:
+//
+//	c.Register("listeners", "http server", srv.Shutdown)
+func (c *Coordinator) Register(name, label string, fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := c.phase(name)
+	p.g.DeferConcurrentLabel(name+"/"+label, func() error {
+		return p.run(fn)
+	})
+}
+
+// phase returns the phase name, declaring it if needed.
+// c.mu must be held.
+func (c *Coordinator) phase(name string) *phase {
+	for _, p := range c.phases {
+		if p.name == name {
+			return p
+		}
+	}
+
+	p := &phase{
+		name: name,
+		g:    goalie.New(c.options...),
+	}
+	c.phases = append(c.phases, p)
+
+	return p
+}
+
+// Shutdown runs all phases and returns every failure as a [goalie.Error],
+// or nil if all cleanups succeed.
+//
+// Each cleanup is labelled "<phase>/<label>" in the returned error.
+// Shutdown returns an error without running any cleanup if the ordering constraints cannot be satisfied.
+// Cleanups run at most once, so Shutdown must not be called more than once.
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	phases, err := c.sortPhases()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range phases {
+		errs = append(errs, p.shutdown(ctx)...)
+	}
+
+	return goalie.Join(errs...)
+}
+
+// sortPhases returns the phases sorted topologically, keeping the declaration order where possible.
+// c.mu must be held.
+func (c *Coordinator) sortPhases() ([]*phase, error) {
+	byName := make(map[string]*phase, len(c.phases))
+	for _, p := range c.phases {
+		byName[p.name] = p
+	}
+	for _, p := range c.phases {
+		for _, name := range p.after {
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("phase %q runs after unknown phase %q", p.name, name)
+			}
+		}
+	}
+
+	sorted := make([]*phase, 0, len(c.phases))
+	done := make(map[string]bool, len(c.phases))
+	for len(sorted) < len(c.phases) {
+		progressed := false
+		for _, p := range c.phases {
+			if done[p.name] {
+				continue
+			}
+			ready := !slices.ContainsFunc(p.after, func(name string) bool {
+				return !done[name]
+			})
+			if !ready {
+				continue
+			}
+
+			sorted = append(sorted, p)
+			done[p.name] = true
+			progressed = true
+
+			// Restart from the first phase to keep the declaration order.
+			break
+		}
+		if !progressed {
+			return nil, errors.New("phases have a cyclic ordering constraint")
+		}
+	}
+
+	return sorted, nil
+}
+
+// shutdown runs all cleanups of p concurrently and returns their failures in registration order.
+func (p *phase) shutdown(ctx context.Context) []error {
+	p.ctx = ctx
+	if p.budget > 0 {
+		var cancel context.CancelFunc
+		p.ctx, cancel = context.WithTimeout(ctx, p.budget)
+		defer cancel()
+	}
+
+	var err error
+	p.g.Collect(&err)
+
+	// Wait for the cleanups exceeding the budget so that they do not run concurrently with the next phase.
+	finished := make(chan struct{})
+	go func() {
+		p.running.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-ctx.Done():
+	}
+
+	var gerr *goalie.Error
+	if !errors.As(err, &gerr) {
+		return nil
+	}
+
+	errs := make([]error, len(gerr.Cleanups))
+	for i, c := range gerr.Cleanups {
+		errs[i] = c
+	}
+
+	return errs
+}
+
+// run runs fn with the context of p, and returns an error wrapping [ErrTimeout]
+// if fn does not return before the context is done.
+func (p *phase) run(fn func(ctx context.Context) error) error {
+	ctx := p.ctx
+	done := make(chan error, 1)
+	p.running.Add(1)
+	go func() {
+		defer p.running.Done()
+
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("cleanup panicked: %v", r)
+			}
+			done <- err
+		}()
+
+		err = fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		select {
+		case err := <-done:
+			return err
+		default:
+			return fmt.Errorf("%w: %w", ErrTimeout, context.Cause(ctx))
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+	"github.com/ras0q/goalie/shutdown"
+)
+
+func assert[T comparable](t *testing.T, expected, got T) {
+	t.Helper()
+	if got != expected {
+		t.Fatalf("assertion failed (expected: %+v, got: %+v)", expected, got)
+	}
+}
+
+var errInternal = errors.New("internal error")
+
+func Test_Coordinator_Order(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	c := shutdown.New()
+	c.Phase("databases", shutdown.After("workers"))
+	c.Phase("listeners")
+	c.Phase("workers", shutdown.After("listeners"))
+	c.Register("databases", "db", record("databases"))
+	c.Register("workers", "worker", record("workers"))
+	c.Register("listeners", "http", record("listeners"))
+	c.Register("queues", "queue", record("queues"))
+
+	err := c.Shutdown(context.Background())
+	assert(t, nil, err)
+	assert(t, 4, len(order))
+	assert(t, "listeners", order[0])
+	assert(t, "workers", order[1])
+	assert(t, "databases", order[2])
+	assert(t, "queues", order[3])
+}
+
+func Test_Coordinator_Concurrent(t *testing.T) {
+	// Both cleanups wait for each other, so they deadlock unless run concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	wait := func(ctx context.Context) error {
+		wg.Done()
+		wg.Wait()
+		return nil
+	}
+
+	c := shutdown.New()
+	c.Phase("workers", shutdown.Budget(5*time.Second))
+	c.Register("workers", "a", wait)
+	c.Register("workers", "b", wait)
+
+	err := c.Shutdown(context.Background())
+	assert(t, nil, err)
+}
+
+func Test_Coordinator_Errors(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errC := errors.New("c")
+
+	c := shutdown.New()
+	c.Phase("first")
+	c.Phase("second", shutdown.After("first"), shutdown.Budget(10*time.Millisecond))
+	c.Register("second", "slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return errC
+	})
+	c.Register("first", "a", func(context.Context) error {
+		// Finish after b to check that errors are in registration order.
+		time.Sleep(10 * time.Millisecond)
+		return errA
+	})
+	c.Register("first", "b", func(context.Context) error { return errB })
+	c.Register("first", "ok", func(context.Context) error { return nil })
+	c.Register("second", "panic", func(context.Context) error { panic("boom") })
+
+	err := c.Shutdown(context.Background())
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, 4, len(gerr.Cleanups))
+	assert(t, "first/a", gerr.Cleanups[0].Label)
+	assert(t, errA, gerr.Cleanups[0].Err)
+	assert(t, "first/b", gerr.Cleanups[1].Label)
+	assert(t, errB, gerr.Cleanups[1].Err)
+	assert(t, "second/slow", gerr.Cleanups[2].Label)
+	assert(t, true, errors.Is(gerr.Cleanups[2], shutdown.ErrTimeout))
+	assert(t, true, errors.Is(gerr.Cleanups[2], context.DeadlineExceeded))
+	assert(t, "second/panic", gerr.Cleanups[3].Label)
+}
+
+func Test_Coordinator_WaitOverBudget(t *testing.T) {
+	type testcase struct {
+		timeout    time.Duration
+		isFinished bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		var finished atomic.Bool
+		var finishedBeforeNext atomic.Bool
+		c := shutdown.New()
+		c.Phase("workers", shutdown.Budget(10*time.Millisecond))
+		c.Phase("databases", shutdown.After("workers"))
+		c.Register("workers", "ignore context", func(context.Context) error {
+			select {
+			case <-time.After(50 * time.Millisecond):
+			case <-release:
+			}
+			finished.Store(true)
+			return nil
+		})
+		c.Register("databases", "db", func(context.Context) error {
+			finishedBeforeNext.Store(finished.Load())
+			return nil
+		})
+
+		ctx := context.Background()
+		if tc.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+			defer cancel()
+		}
+
+		err := c.Shutdown(ctx)
+		assert(t, true, errors.Is(err, shutdown.ErrTimeout))
+		assert(t, tc.isFinished, finishedBeforeNext.Load())
+	}
+
+	testcases := map[string]testcase{
+		"wait before next phase": {
+			isFinished: true,
+		},
+		"give up when shutdown context is done": {
+			timeout: 20 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_Coordinator_Options(t *testing.T) {
+	errWrapped := errors.New("wrapped")
+
+	c := shutdown.New(goalie.WithWrapErrorFunc(func(err error) error {
+		return errors.Join(errWrapped, err)
+	}))
+	c.Register("workers", "worker", func(context.Context) error { return errInternal })
+
+	err := c.Shutdown(context.Background())
+
+	var gerr *goalie.Error
+	assert(t, true, errors.As(err, &gerr))
+	assert(t, 1, len(gerr.Cleanups))
+	assert(t, true, errors.Is(gerr.Cleanups[0], errWrapped))
+	assert(t, true, errors.Is(gerr.Cleanups[0], errInternal))
+	assert(t, true, strings.Contains(gerr.Cleanups[0].Site, "shutdown_test.go"))
+}
+
+func Test_Coordinator_InvalidOrder(t *testing.T) {
+	type testcase struct {
+		setup func(c *shutdown.Coordinator)
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		ran := false
+		c := shutdown.New()
+		c.Register("first", "cleanup", func(context.Context) error {
+			ran = true
+			return nil
+		})
+		tc.setup(c)
+
+		err := c.Shutdown(context.Background())
+		assert(t, true, err != nil)
+		assert(t, false, ran)
+	}
+
+	testcases := map[string]testcase{
+		"cyclic phases": {
+			setup: func(c *shutdown.Coordinator) {
+				c.Phase("first", shutdown.After("second"))
+				c.Phase("second", shutdown.After("first"))
+			},
+		},
+		"unknown phase": {
+			setup: func(c *shutdown.Coordinator) {
+				c.Phase("first", shutdown.After("unknown"))
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
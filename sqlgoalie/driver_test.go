@@ -0,0 +1,142 @@
+package sqlgoalie_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeDB is the state of a fake database/sql driver.
+type fakeDB struct {
+	mu sync.Mutex
+
+	commitErr   error
+	rollbackErr error
+
+	// rows is the number of rows returned by a query, followed by rowsErr if set.
+	rows         int
+	rowsErr      error
+	rowsCloseErr error
+	stmtCloseErr error
+
+	commits   int
+	rollbacks int
+}
+
+// open opens a *sql.DB backed by db.
+func (db *fakeDB) open(t *testing.T) *sql.DB {
+	t.Helper()
+
+	sqlDB := sql.OpenDB(fakeConnector{db: db})
+	t.Cleanup(func() {
+		if err := sqlDB.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return sqlDB
+}
+
+type fakeConnector struct {
+	db *fakeDB
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: c.db}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("use fakeConnector")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return &fakeTx{db: c.db}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+
+	tx.db.commits++
+	return tx.db.commitErr
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+
+	tx.db.rollbacks++
+	return tx.db.rollbackErr
+}
+
+type fakeStmt struct {
+	db *fakeDB
+}
+
+func (s *fakeStmt) Close() error {
+	return s.db.stmtCloseErr
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{db: s.db}, nil
+}
+
+type fakeRows struct {
+	db   *fakeDB
+	read int
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"n"}
+}
+
+func (r *fakeRows) Close() error {
+	return r.db.rowsCloseErr
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.read < r.db.rows {
+		dest[0] = int64(r.read)
+		r.read++
+		return nil
+	}
+	if r.db.rowsErr != nil {
+		return r.db.rowsErr
+	}
+
+	return io.EOF
+}
@@ -0,0 +1,54 @@
+package sqlgoalie
+
+import (
+	"database/sql"
+
+	"github.com/ras0q/goalie"
+)
+
+// GuardRows checks rows.Err and closes rows, capturing each error separately with g.
+//
+// This catches an error that interrupted the iteration,
+// which is silently ignored when only rows.Close is checked.
+//
+// Example:
+//
+//	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+//	if err != nil {
+//		return err
+//	}
+//	defer sqlgoalie.GuardRows(g, rows)
+//
+//	for rows.Next() {
+//		// ...
+//	}
+func GuardRows(g *goalie.Goalie, rows *sql.Rows) {
+	g.GuardLabel("(*sql.Rows).Err", rows.Err)
+	g.GuardLabel("(*sql.Rows).Close", rows.Close)
+}
+
+// GuardStmt closes stmt, capturing the error with g.
+//
+// Example:
+//
+//	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (name) VALUES (?)")
+//	if err != nil {
+//		return err
+//	}
+//	defer sqlgoalie.GuardStmt(g, stmt)
+func GuardStmt(g *goalie.Goalie, stmt *sql.Stmt) {
+	g.GuardLabel("(*sql.Stmt).Close", stmt.Close)
+}
+
+// GuardConn returns conn to the connection pool, capturing the error with g.
+//
+// Example:
+//
+//	conn, err := db.Conn(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer sqlgoalie.GuardConn(g, conn)
+func GuardConn(g *goalie.Goalie, conn *sql.Conn) {
+	g.GuardLabel("(*sql.Conn).Close", conn.Close)
+}
@@ -0,0 +1,124 @@
+package sqlgoalie_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ras0q/goalie"
+	"github.com/ras0q/goalie/sqlgoalie"
+)
+
+func Test_GuardRows(t *testing.T) {
+	errRows := errors.New("rows")
+	errRowsClose := errors.New("rows close")
+	errStmtClose := errors.New("stmt close")
+
+	type testcase struct {
+		db             *fakeDB
+		breakEarly     bool
+		closeConnEarly bool
+		expectedRead   int
+		expectedLabels []string
+		expectedErrs   []error
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		sqlDB := tc.db.open(t)
+		read := 0
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+
+			ctx := context.Background()
+			conn, err := sqlDB.Conn(ctx)
+			if err != nil {
+				return err
+			}
+			defer sqlgoalie.GuardConn(g, conn)
+
+			stmt, err := conn.PrepareContext(ctx, "SELECT n")
+			if err != nil {
+				return err
+			}
+			defer sqlgoalie.GuardStmt(g, stmt)
+
+			rows, err := stmt.QueryContext(ctx)
+			if err != nil {
+				return err
+			}
+			defer sqlgoalie.GuardRows(g, rows)
+
+			for rows.Next() {
+				read++
+				if tc.breakEarly {
+					break
+				}
+			}
+
+			if tc.closeConnEarly {
+				// Close the rows first, since the connection waits for them.
+				_ = rows.Close()
+				_ = conn.Close()
+			}
+
+			return nil
+		}()
+		assert(t, tc.expectedRead, read)
+
+		if len(tc.expectedErrs) == 0 {
+			assert(t, nil, err)
+			return
+		}
+
+		var gerr *goalie.Error
+		assert(t, true, errors.As(err, &gerr))
+		assert(t, len(tc.expectedErrs), len(gerr.Cleanups))
+		for i, c := range gerr.Cleanups {
+			assert(t, tc.expectedLabels[i], c.Label)
+			assert(t, true, errors.Is(c, tc.expectedErrs[i]))
+		}
+	}
+
+	testcases := map[string]testcase{
+		"no errors": {
+			db:           &fakeDB{rows: 3},
+			expectedRead: 3,
+		},
+		"iteration error": {
+			db:             &fakeDB{rows: 2, rowsErr: errRows},
+			expectedRead:   2,
+			expectedLabels: []string{"(*sql.Rows).Err"},
+			expectedErrs:   []error{errRows},
+		},
+		"rows close error after early break": {
+			db:             &fakeDB{rows: 3, rowsCloseErr: errRowsClose},
+			breakEarly:     true,
+			expectedRead:   1,
+			expectedLabels: []string{"(*sql.Rows).Close"},
+			expectedErrs:   []error{errRowsClose},
+		},
+		"stmt close error": {
+			db:             &fakeDB{rows: 1, stmtCloseErr: errStmtClose},
+			expectedRead:   1,
+			expectedLabels: []string{"(*sql.Stmt).Close"},
+			expectedErrs:   []error{errStmtClose},
+		},
+		"conn closed twice": {
+			db:             &fakeDB{rows: 1},
+			closeConnEarly: true,
+			expectedRead:   1,
+			expectedLabels: []string{"(*sql.Conn).Close"},
+			expectedErrs:   []error{sql.ErrConnDone},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Package sqlgoalie provides database/sql helpers for Goalie.
+package sqlgoalie
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ras0q/goalie"
+)
+
+// TxBeginner is implemented by [*sql.DB] and [*sql.Conn].
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// WithTx runs fn in a transaction started on db.
+//
+// The transaction is committed if fn returns nil, and rolled back if fn returns an error or panics.
+// A rollback failure is reported alongside the error of fn,
+// while [sql.ErrTxDone] from rolling back a finished transaction is ignored.
+//
+// Example:
+//
+//	err := sqlgoalie.WithTx(ctx, db, nil, func(tx *sql.Tx) error {
+//		_, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - 100 WHERE id = ?", id)
+//		return err
+//	})
+func WithTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	g := goalie.New()
+	defer g.Collect(&err)
+
+	// Rollback runs even if fn panics.
+	defer g.GuardLabel("(*sql.Tx).Rollback", func() error {
+		return ignoreTxDone(tx.Rollback())
+	})
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ignoreTxDone returns nil if err is [sql.ErrTxDone], or err otherwise.
+func ignoreTxDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+
+	return err
+}
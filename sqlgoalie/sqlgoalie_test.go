@@ -0,0 +1,107 @@
+package sqlgoalie_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ras0q/goalie"
+	"github.com/ras0q/goalie/sqlgoalie"
+)
+
+func assert[T comparable](t *testing.T, expected, got T) {
+	t.Helper()
+	if got != expected {
+		t.Fatalf("assertion failed (expected: %+v, got: %+v)", expected, got)
+	}
+}
+
+func Test_WithTx(t *testing.T) {
+	errFn := errors.New("fn")
+	errCommit := errors.New("commit")
+	errRollback := errors.New("rollback")
+
+	type testcase struct {
+		fnErr             error
+		fnPanic           bool
+		commitErr         error
+		rollbackErr       error
+		expectedCommits   int
+		expectedRollbacks int
+		isFnError         bool
+		isCommitError     bool
+		isRollbackError   bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		db := &fakeDB{
+			commitErr:   tc.commitErr,
+			rollbackErr: tc.rollbackErr,
+		}
+		sqlDB := db.open(t)
+
+		var err error
+		panicked := func() (panicked bool) {
+			defer func() {
+				panicked = recover() != nil
+			}()
+
+			err = sqlgoalie.WithTx(context.Background(), sqlDB, nil, func(tx *sql.Tx) error {
+				if tc.fnPanic {
+					panic("boom")
+				}
+				return tc.fnErr
+			})
+
+			return false
+		}()
+		assert(t, tc.fnPanic, panicked)
+		assert(t, tc.expectedCommits, db.commits)
+		assert(t, tc.expectedRollbacks, db.rollbacks)
+		assert(t, tc.isFnError, errors.Is(err, errFn))
+		assert(t, tc.isCommitError, errors.Is(err, errCommit))
+		assert(t, tc.isRollbackError, errors.Is(err, errRollback))
+
+		if tc.isRollbackError {
+			var gerr *goalie.Error
+			assert(t, true, errors.As(err, &gerr))
+			assert(t, "(*sql.Tx).Rollback", gerr.Cleanups[0].Label)
+		}
+	}
+
+	testcases := map[string]testcase{
+		"commit on success": {
+			expectedCommits: 1,
+		},
+		"rollback on error": {
+			fnErr:             errFn,
+			expectedRollbacks: 1,
+			isFnError:         true,
+		},
+		"rollback on panic": {
+			fnPanic:           true,
+			expectedRollbacks: 1,
+		},
+		"report commit error": {
+			commitErr:       errCommit,
+			expectedCommits: 1,
+			isCommitError:   true,
+		},
+		"report rollback error alongside fn error": {
+			fnErr:             errFn,
+			rollbackErr:       errRollback,
+			expectedRollbacks: 1,
+			isFnError:         true,
+			isRollbackError:   true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package goalie
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSlowCleanup is reported when a cleanup takes longer than the threshold set by
+// [WithSlowCleanupThreshold] and no [SlowCleanupFunc] is set.
+var ErrSlowCleanup = errors.New("cleanup is slow")
+
+// WithClock sets the clock used to measure how long each cleanup takes.
+// By default, [time.Now] is used.
+//
+// This is useful for testing slow cleanups without sleeping.
+func WithClock(now func() time.Time) Option {
+	return func(g *Goalie) {
+		g.clock = now
+	}
+}
+
+// WithSlowCleanupThreshold sets the duration after which a cleanup is considered slow.
+//
+// A slow cleanup is reported to the [SlowCleanupFunc] if set,
+// otherwise it is recorded as an error wrapping [ErrSlowCleanup]
+// even if the cleanup succeeds.
+func WithSlowCleanupThreshold(threshold time.Duration) Option {
+	return func(g *Goalie) {
+		g.slowThreshold = threshold
+	}
+}
+
+// SlowCleanupFunc is a function type called when a cleanup is slow.
+// It may be called concurrently for cleanups registered with [Goalie.DeferConcurrent].
+type SlowCleanupFunc func(label, site string, duration time.Duration)
+
+// WithSlowCleanupFunc sets the function called when a cleanup takes longer than
+// the threshold set by [WithSlowCleanupThreshold], instead of recording an error.
+//
+// Example:
+//
+//	g := goalie.New(
+//		goalie.WithSlowCleanupThreshold(time.Second),
+//		goalie.WithSlowCleanupFunc(func(label, site string, duration time.Duration) {
+//			slog.Warn("slow cleanup", "label", label, "site", site, "duration", duration)
+//		}),
+//	)
+func WithSlowCleanupFunc(slowCleanupFunc SlowCleanupFunc) Option {
+	return func(g *Goalie) {
+		g.slowCleanupFunc = slowCleanupFunc
+	}
+}
+
+// now returns the clock of g.
+func (g *Goalie) now() func() time.Time {
+	if g.clock == nil {
+		return time.Now
+	}
+
+	return g.clock
+}
+
+// isSlow reports whether a cleanup that took duration exceeds the slow threshold.
+func (g *Goalie) isSlow(duration time.Duration) bool {
+	return g.slowThreshold > 0 && duration > g.slowThreshold
+}
+
+// checkSlow reports a cleanup that took duration if it is slow.
+// It returns a warning error if the cleanup should be recorded as failed.
+func (g *Goalie) checkSlow(label, site string, duration time.Duration) error {
+	if !g.isSlow(duration) {
+		return nil
+	}
+
+	if g.slowCleanupFunc != nil {
+		g.slowCleanupFunc(label, site, duration)
+		return nil
+	}
+
+	return fmt.Errorf("%w: took %s (threshold: %s)", ErrSlowCleanup, duration, g.slowThreshold)
+}
@@ -0,0 +1,124 @@
+package goalie_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+// fakeClock is a clock that only advances when Advance is called.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Sleep advances the clock by d and always succeeds, like a cleanup that takes d.
+func (c *fakeClock) Sleep(d time.Duration) error {
+	c.Advance(d)
+	return nil
+}
+
+func Test_WithSlowCleanupThreshold(t *testing.T) {
+	errCleanup := errors.New("cleanup")
+
+	type testcase struct {
+		duration          time.Duration
+		cleanupErr        error
+		useSlowFunc       bool
+		isSlowCleanupFunc bool
+		isSlowCleanupErr  bool
+		isCleanupErr      bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		options := []goalie.Option{
+			goalie.WithClock(clock.Now),
+			goalie.WithSlowCleanupThreshold(time.Second),
+		}
+
+		var slowLabel string
+		var slowDuration time.Duration
+		if tc.useSlowFunc {
+			options = append(options, goalie.WithSlowCleanupFunc(func(label, site string, duration time.Duration) {
+				slowLabel = label
+				slowDuration = duration
+			}))
+		}
+
+		err := func() (err error) {
+			g := goalie.New(options...)
+			defer g.Collect(&err)
+
+			g.Defer(func() error {
+				clock.Advance(tc.duration)
+				return tc.cleanupErr
+			})
+
+			return nil
+		}()
+		assert(t, tc.isSlowCleanupFunc, slowLabel != "")
+		assert(t, tc.isSlowCleanupErr, errors.Is(err, goalie.ErrSlowCleanup))
+		assert(t, tc.isCleanupErr, errors.Is(err, errCleanup))
+
+		if tc.isSlowCleanupFunc {
+			assert(t, tc.duration, slowDuration)
+		}
+
+		var gerr *goalie.Error
+		if errors.As(err, &gerr) {
+			assert(t, 1, len(gerr.Cleanups))
+			assert(t, tc.duration, gerr.Cleanups[0].Duration)
+		}
+	}
+
+	testcases := map[string]testcase{
+		"fast cleanup": {
+			duration: time.Second,
+		},
+		"fast failed cleanup": {
+			duration:     time.Second,
+			cleanupErr:   errCleanup,
+			isCleanupErr: true,
+		},
+		"slow cleanup": {
+			duration:         2 * time.Second,
+			isSlowCleanupErr: true,
+		},
+		"slow failed cleanup": {
+			duration:         2 * time.Second,
+			cleanupErr:       errCleanup,
+			isSlowCleanupErr: true,
+			isCleanupErr:     true,
+		},
+		"slow cleanup with func": {
+			duration:          2 * time.Second,
+			useSlowFunc:       true,
+			isSlowCleanupFunc: true,
+		},
+		"slow failed cleanup with func": {
+			duration:          2 * time.Second,
+			cleanupErr:        errCleanup,
+			useSlowFunc:       true,
+			isSlowCleanupFunc: true,
+			isCleanupErr:      true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
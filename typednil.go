@@ -0,0 +1,46 @@
+package goalie
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrTypedNil is reported when a cleanup returns a typed-nil error and [TypedNilAsMisuse] is set.
+var ErrTypedNil = errors.New("cleanup returned a typed-nil error")
+
+// TypedNilPolicy determines how a Goalie handles a typed-nil error,
+// i.e. a non-nil error interface holding a nil pointer, map, slice, etc.
+//
+// Such an error typically comes from a cleanup that returns a concrete `*MyError`:
+//
+//	func (c *Conn) Close() *MyError { return nil }
+//
+//	defer g.Guard(func() error { return c.Close() }) // never nil!
+type TypedNilPolicy int
+
+const (
+	// TypedNilAsError records a typed-nil error as it is. This is the default.
+	TypedNilAsError TypedNilPolicy = iota
+	// TypedNilAsSuccess treats a typed-nil error as a successful cleanup.
+	TypedNilAsSuccess
+	// TypedNilAsMisuse records a typed-nil error as [ErrTypedNil].
+	TypedNilAsMisuse
+)
+
+// WithTypedNilPolicy sets how typed-nil errors returned by cleanups are handled.
+func WithTypedNilPolicy(policy TypedNilPolicy) Option {
+	return func(g *Goalie) {
+		g.typedNilPolicy = policy
+	}
+}
+
+// isTypedNil reports whether err is a non-nil interface holding a nil value.
+func isTypedNil(err error) bool {
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
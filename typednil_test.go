@@ -0,0 +1,115 @@
+package goalie_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ras0q/goalie"
+)
+
+type ptrError struct{}
+
+func (*ptrError) Error() string { return "pointer error" }
+
+type mapError map[string]string
+
+func (mapError) Error() string { return "map error" }
+
+type ifaceError interface {
+	error
+	Temporary() bool
+}
+
+func Test_WithTypedNilPolicy(t *testing.T) {
+	type testcase struct {
+		policy           goalie.TypedNilPolicy
+		errFunc          func() error
+		isCollectedError bool
+		isTypedNilError  bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		err := func() (err error) {
+			g := goalie.New(goalie.WithTypedNilPolicy(tc.policy))
+			defer g.Collect(&err)
+
+			defer g.Guard(tc.errFunc)
+
+			return nil
+		}()
+		assert(t, tc.isCollectedError, err != nil)
+		assert(t, tc.isTypedNilError, errors.Is(err, goalie.ErrTypedNil))
+	}
+
+	nilPtr := func() error {
+		var err *ptrError
+		return err
+	}
+	nilMap := func() error {
+		var err mapError
+		return err
+	}
+	nilIface := func() error {
+		var err ifaceError
+		return err
+	}
+
+	testcases := map[string]testcase{
+		"pointer as error": {
+			policy:           goalie.TypedNilAsError,
+			errFunc:          nilPtr,
+			isCollectedError: true,
+			isTypedNilError:  false,
+		},
+		"pointer as success": {
+			policy:           goalie.TypedNilAsSuccess,
+			errFunc:          nilPtr,
+			isCollectedError: false,
+			isTypedNilError:  false,
+		},
+		"pointer as misuse": {
+			policy:           goalie.TypedNilAsMisuse,
+			errFunc:          nilPtr,
+			isCollectedError: true,
+			isTypedNilError:  true,
+		},
+		"map as success": {
+			policy:           goalie.TypedNilAsSuccess,
+			errFunc:          nilMap,
+			isCollectedError: false,
+			isTypedNilError:  false,
+		},
+		"map as misuse": {
+			policy:           goalie.TypedNilAsMisuse,
+			errFunc:          nilMap,
+			isCollectedError: true,
+			isTypedNilError:  true,
+		},
+		"nil interface is always nil": {
+			policy:           goalie.TypedNilAsMisuse,
+			errFunc:          nilIface,
+			isCollectedError: false,
+			isTypedNilError:  false,
+		},
+		"non-nil pointer as misuse": {
+			policy:           goalie.TypedNilAsMisuse,
+			errFunc:          func() error { return &ptrError{} },
+			isCollectedError: true,
+			isTypedNilError:  false,
+		},
+		"non-nil map as success": {
+			policy:           goalie.TypedNilAsSuccess,
+			errFunc:          func() error { return mapError{} },
+			isCollectedError: true,
+			isTypedNilError:  false,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
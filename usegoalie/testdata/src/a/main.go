@@ -20,6 +20,9 @@ func run() (int, error) {
 	s := S{}
 	defer s.f()        // want `missed error in defer statement: s\.f\(\)`
 	defer s.g("world") // want `missed error in defer statement: s\.g\(\"world\"\)`
+	defer s.h()        // want `missed error in defer statement: s\.h\(\)`
+	defer s.e("error") // want `missed error in defer statement: s\.e\(\"error\"\)`
+	defer s.r()        // want `missed error in defer statement: s\.r\(\)`
 
 	return 0, nil
 }
@@ -41,3 +44,22 @@ func (S) f() error {
 func (S) g(s string) error {
 	return fmt.Errorf("error from g(%s)", s)
 }
+
+func (S) h() (int, error) {
+	return 0, fmt.Errorf("error from h()")
+}
+
+// E is a concrete error type.
+type E struct{}
+
+func (*E) Error() string {
+	return "error from E"
+}
+
+func (S) e(s string) *E {
+	return &E{}
+}
+
+func (S) r() (int, *E) {
+	return 0, &E{}
+}
@@ -27,6 +27,9 @@ const goalieImportPath = "github.com/ras0q/goalie"
 
 var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
 
+// universeError is the predeclared error type itself, unlike errorType which is its underlying interface.
+var universeError = types.Universe.Lookup("error").Type()
+
 type FuncInfo struct {
 	ReturnsError     bool
 	NamedErrorVar    string
@@ -278,14 +281,57 @@ func buildFuncSetupEdits(pass *analysis.Pass, funcDecl *ast.FuncDecl) []analysis
 func buildDeferEdit(pass *analysis.Pass, deferStmt *ast.DeferStmt, sig *types.Signature) analysis.TextEdit {
 	var newDeferText string
 
-	// check if the function is `func () error`
-	isFuncWithErrorOnly := sig.Params().Len() == 0 &&
-		sig.Results().Len() == 1 &&
+	// check if the function returns only an error, e.g. `func (A, B) error`
+	returnsErrorOnly := sig.Results().Len() == 1 &&
 		types.Implements(sig.Results().At(0).Type(), errorType)
-	if isFuncWithErrorOnly {
-		funcExprStr := render(pass, deferStmt.Call.Fun) // f.Close
+	// check if the function returns exactly `error`, which the generic helpers require
+	// since a function returning a concrete error type is not assignable to them
+	returnsExactErrorOnly := sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), universeError)
+	// check if the function is `func () (T, error)`
+	returnsResultAndError := sig.Params().Len() == 0 &&
+		sig.Results().Len() == 2 &&
+		types.Identical(sig.Results().At(1).Type(), universeError)
+	funcExprStr := render(pass, deferStmt.Call.Fun) // f.Close
+	switch {
+	case returnsErrorOnly && sig.Params().Len() == 0:
 		newDeferText = fmt.Sprintf("defer g.Guard(%s)", funcExprStr)
-	} else {
+	case returnsExactErrorOnly && !sig.Variadic() &&
+		(sig.Params().Len() == 1 || sig.Params().Len() == 2) &&
+		len(deferStmt.Call.Args) == sig.Params().Len():
+		args := make([]string, 0, len(deferStmt.Call.Args))
+		for _, arg := range deferStmt.Call.Args {
+			args = append(args, render(pass, arg))
+		}
+		newDeferText = fmt.Sprintf(
+			"defer goalie.Guard%d(g, %s, %s)",
+			sig.Params().Len(),
+			funcExprStr,
+			strings.Join(args, ", "),
+		)
+	case returnsResultAndError:
+		newDeferText = fmt.Sprintf("defer goalie.GuardResult(g, %s)", funcExprStr)
+	case sig.Results().Len() > 1:
+		// discard all results but the last error, e.g. `_, err := f()`
+		lhs := make([]string, sig.Results().Len())
+		errIndex := -1
+		for i := range lhs {
+			lhs[i] = "_"
+			if types.Implements(sig.Results().At(i).Type(), errorType) {
+				errIndex = i
+			}
+		}
+		lhs[errIndex] = "err"
+		callExprStr := render(pass, deferStmt.Call) // f.Close(params)
+		newDeferText = fmt.Sprintf(
+			"defer g.Guard(func () error {\n"+
+				"\t%s := %s\n"+
+				"\treturn err\n"+
+				"})",
+			strings.Join(lhs, ", "),
+			callExprStr,
+		)
+	default:
 		callExprStr := render(pass, deferStmt.Call) // f.Close(params)
 		newDeferText = fmt.Sprintf(
 			"defer g.Guard(func () error {\n"+
@@ -0,0 +1,102 @@
+package goalie
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrWaitTimeout is reported when a background goroutine does not finish within the timeout.
+var ErrWaitTimeout = errors.New("timed out waiting for a background goroutine")
+
+// GuardChan waits for an error from errc and captures it.
+// If no error is received within timeout, an error wrapping [ErrWaitTimeout] is captured instead.
+// A closed errc is considered successful, and a non-positive timeout means no timeout.
+//
+// Example:
+//
+//	errc := make(chan error, 1)
+//	go func() {
+//		err := srv.Serve(l)
+//		if errors.Is(err, http.ErrServerClosed) {
+//			// Serve always returns this error after Close, so it is not a failure.
+//			err = nil
+//		}
+//		errc <- err
+//	}()
+//	defer g.GuardChan(errc, 5*time.Second)
+//	defer g.Guard(srv.Close)
+func (g *Goalie) GuardChan(errc <-chan error, timeout time.Duration) {
+	g.guard(origin{label: "wait for error channel"}, func() error {
+		timer, stop := newTimeout(timeout)
+		defer stop()
+
+		select {
+		case err := <-errc:
+			return err
+		case <-timer:
+			return fmt.Errorf("%w after %s", ErrWaitTimeout, timeout)
+		}
+	})
+}
+
+// GuardDone waits for done to be closed, capturing an error wrapping [ErrWaitTimeout]
+// if it is not closed within timeout.
+// A non-positive timeout means no timeout.
+func (g *Goalie) GuardDone(done <-chan struct{}, timeout time.Duration) {
+	g.guard(origin{label: "wait for done channel"}, func() error {
+		return waitDone(done, timeout)
+	})
+}
+
+// GuardWait waits for wg, capturing an error wrapping [ErrWaitTimeout]
+// if it does not finish within timeout.
+// A non-positive timeout means no timeout.
+//
+// Example:
+//
+//	var wg sync.WaitGroup
+//	defer g.GuardWait(&wg, 5*time.Second)
+//	for _, job := range jobs {
+//		wg.Add(1)
+//		go func() {
+//			defer wg.Done()
+//			job.Run(ctx)
+//		}()
+//	}
+func (g *Goalie) GuardWait(wg *sync.WaitGroup, timeout time.Duration) {
+	g.guard(origin{label: "wait for WaitGroup"}, func() error {
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		return waitDone(done, timeout)
+	})
+}
+
+// waitDone waits for done to be closed within timeout.
+func waitDone(done <-chan struct{}, timeout time.Duration) error {
+	timer, stop := newTimeout(timeout)
+	defer stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer:
+		return fmt.Errorf("%w after %s", ErrWaitTimeout, timeout)
+	}
+}
+
+// newTimeout returns a channel that receives after timeout, and a function to release it.
+// If timeout is not positive, the channel never receives.
+func newTimeout(timeout time.Duration) (<-chan time.Time, func()) {
+	if timeout <= 0 {
+		return nil, func() {}
+	}
+
+	timer := time.NewTimer(timeout)
+	return timer.C, func() { timer.Stop() }
+}
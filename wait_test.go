@@ -0,0 +1,185 @@
+package goalie_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ras0q/goalie"
+)
+
+func Test_GuardChan(t *testing.T) {
+	type testcase struct {
+		send          error
+		close         bool
+		timeout       time.Duration
+		isNil         bool
+		isInternal    bool
+		isWaitTimeout bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		errc := make(chan error, 1)
+		if tc.send != nil {
+			errc <- tc.send
+		}
+		if tc.close {
+			close(errc)
+		}
+
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+			defer g.GuardChan(errc, tc.timeout)
+
+			return nil
+		}()
+		assert(t, tc.isNil, err == nil)
+		assert(t, tc.isInternal, errors.Is(err, errInternal))
+		assert(t, tc.isWaitTimeout, errors.Is(err, goalie.ErrWaitTimeout))
+
+		if err != nil {
+			var gerr *goalie.Error
+			assert(t, true, errors.As(err, &gerr))
+			assert(t, "wait for error channel", gerr.Cleanups[0].Label)
+		}
+	}
+
+	testcases := map[string]testcase{
+		"capture received error": {
+			send:       errInternal,
+			timeout:    time.Second,
+			isInternal: true,
+		},
+		"closed channel is successful": {
+			close:   true,
+			timeout: time.Second,
+			isNil:   true,
+		},
+		"time out": {
+			timeout:       10 * time.Millisecond,
+			isWaitTimeout: true,
+		},
+		"no timeout": {
+			send:       errInternal,
+			isInternal: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_GuardDone(t *testing.T) {
+	type testcase struct {
+		close         bool
+		timeout       time.Duration
+		isWaitTimeout bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		done := make(chan struct{})
+		if tc.close {
+			close(done)
+		}
+
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+			defer g.GuardDone(done, tc.timeout)
+
+			return nil
+		}()
+		assert(t, !tc.isWaitTimeout, err == nil)
+		assert(t, tc.isWaitTimeout, errors.Is(err, goalie.ErrWaitTimeout))
+	}
+
+	testcases := map[string]testcase{
+		"closed": {
+			close:   true,
+			timeout: time.Second,
+		},
+		"time out": {
+			timeout:       10 * time.Millisecond,
+			isWaitTimeout: true,
+		},
+		"no timeout": {
+			close: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}
+
+func Test_GuardWait(t *testing.T) {
+	type testcase struct {
+		work          time.Duration
+		timeout       time.Duration
+		isWaitTimeout bool
+	}
+
+	run := func(t *testing.T, tc testcase) {
+		t.Helper()
+
+		var wg sync.WaitGroup
+		finished := make(chan struct{})
+		err := func() (err error) {
+			g := goalie.New()
+			defer g.Collect(&err)
+			defer g.GuardWait(&wg, tc.timeout)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(finished)
+				time.Sleep(tc.work)
+			}()
+
+			return nil
+		}()
+		assert(t, !tc.isWaitTimeout, err == nil)
+		assert(t, tc.isWaitTimeout, errors.Is(err, goalie.ErrWaitTimeout))
+
+		if !tc.isWaitTimeout {
+			select {
+			case <-finished:
+			default:
+				t.Fatal("GuardWait returned before the goroutine finished")
+			}
+		}
+		<-finished
+	}
+
+	testcases := map[string]testcase{
+		"wait for goroutine": {
+			work:    10 * time.Millisecond,
+			timeout: time.Second,
+		},
+		"time out": {
+			work:          200 * time.Millisecond,
+			timeout:       10 * time.Millisecond,
+			isWaitTimeout: true,
+		},
+		"no timeout": {
+			work: 10 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			run(t, tc)
+		})
+	}
+}